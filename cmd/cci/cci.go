@@ -0,0 +1,431 @@
+package cci
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// NewCmd creates a new CCI command
+func NewCmd() *cobra.Command {
+	cciCmd := &cobra.Command{
+		Use:   "cci",
+		Short: "Look up Control Correlation Identifiers",
+		Long: `Look up DISA Control Correlation Identifiers (CCIs), the NIST SP 800-53
+controls they map to, and how well a checklist covers them.`,
+	}
+
+	// Add global flags for all CCI subcommands
+	cciCmd.PersistentFlags().String("cci-map", "", "Path to a custom CCI XML document (optional, uses the imported or embedded CCI list if not specified)")
+	cciCmd.PersistentFlags().String("nist-revision", "5", "NIST SP 800-53 revision to map CCIs to: 4 or 5")
+	cciCmd.PersistentFlags().Bool("no-fallback", false, "Do not fall back to other NIST references for CCIs without a reference to the selected revision")
+
+	// Bind flags to viper
+	if err := viper.BindPFlag("cci.cciMap", cciCmd.PersistentFlags().Lookup("cci-map")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("cci.nistRevision", cciCmd.PersistentFlags().Lookup("nist-revision")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("cci.noFallback", cciCmd.PersistentFlags().Lookup("no-fallback")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+
+	cciCmd.AddCommand(newShowCmd())
+	cciCmd.AddCommand(newControlCmd())
+	cciCmd.AddCommand(newCoverageCmd())
+	cciCmd.AddCommand(newDiffCmd())
+	cciCmd.AddCommand(newImportCmd())
+	cciCmd.AddCommand(newVersionCmd())
+
+	return cciCmd
+}
+
+// newShowCmd creates a show subcommand
+func newShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <CCI>",
+		Short: "Show a CCI, its references and the control it maps to",
+		Args:  cobra.ExactArgs(1),
+		RunE:  showCCI,
+	}
+}
+
+// newControlCmd creates a control subcommand
+func newControlCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "control <control-id>",
+		Short: "List the CCIs that map to a control",
+		Long: `List the CCIs that map to a control or control enhancement, given as an OSCAL
+control id such as ac-2 or ac-2.4.`,
+		Args: cobra.ExactArgs(1),
+		RunE: showControl,
+	}
+}
+
+// newCoverageCmd creates a coverage subcommand
+func newCoverageCmd() *cobra.Command {
+	coverageCmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Report which CCIs of each control a checklist covers",
+		Long: `Report, for every control cited by a checklist, which of its CCIs are satisfied
+by not_a_finding rules, which are open or not yet reviewed, and which no rule
+of the checklist covers. A control is met when all of its CCIs are satisfied
+or not applicable.`,
+		RunE: showCoverage,
+	}
+
+	coverageCmd.Flags().StringP("input", "i", "", "Path to the STIG checklist (required)")
+
+	if err := viper.BindPFlag("cci.coverage.input", coverageCmd.Flags().Lookup("input")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := coverageCmd.MarkFlagRequired("input"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return coverageCmd
+}
+
+// newDiffCmd creates a diff subcommand
+func newDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff <old.xml> [new.xml]",
+		Short: "Compare two versions of the CCI list",
+		Long: `Compare two versions of the DISA CCI list and report added and removed CCIs,
+changed definitions and statuses, and CCIs that map to a different control
+under the selected NIST revision. If only one list is given, it is compared
+with the CCI list in use (see 'oscalctl cci version').
+
+With --components, every OSCAL component definition given (or found in the
+given directories) is searched for the CCIs it cites, and component
+definitions citing removed or changed CCIs are listed.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: diffCCI,
+	}
+
+	diffCmd.Flags().StringSlice("components", nil, "Component definitions or directories of them to check for affected CCIs (optional)")
+
+	if err := viper.BindPFlag("cci.diff.components", diffCmd.Flags().Lookup("components")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+
+	return diffCmd
+}
+
+// newImportCmd creates an import subcommand
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <U_CCI_List.zip>",
+		Short: "Import the CCI list from DISA's distribution zip",
+		Long: `Import the CCI list from DISA's distribution zip, or from the XML document
+itself, into the oscalctl data directory ($HOME/.oscalctl/cci).
+
+CCI lists are resolved in the order --cci-map flag, cci.cciMap or
+oscal.component.cciMap configuration, imported list, embedded list.`,
+		Args: cobra.ExactArgs(1),
+		RunE: importCCI,
+	}
+}
+
+// newVersionCmd creates a version subcommand
+func newVersionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Show which CCI list is in use and its version",
+		Args:  cobra.NoArgs,
+		RunE:  showVersion,
+	}
+}
+
+// importCCI handles the cci import command
+func importCCI(cmd *cobra.Command, args []string) error {
+	index, path, err := cciparsing.ImportCCIList(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to import CCI list: %w", err)
+	}
+
+	fmt.Printf("Imported CCI list %s (published %s) with %d CCIs: %s\n", index.Version, index.PublishDate, index.Len(), path)
+	return nil
+}
+
+// showVersion handles the cci version command
+func showVersion(cmd *cobra.Command, args []string) error {
+	source := cciparsing.ResolveCCISource(cciMapPath())
+	index, err := source.Load()
+	if err != nil {
+		return fmt.Errorf("failed to parse CCI document: %w", err)
+	}
+
+	origin := source.Kind
+	if source.Kind == cciparsing.SourceCustom {
+		origin = "config"
+		if cmd.Flags().Changed("cci-map") {
+			origin = "flag"
+		}
+	}
+
+	fmt.Printf("Source: %s\n", origin)
+	if source.Path != "" {
+		fmt.Printf("Path: %s\n", source.Path)
+	}
+	fmt.Printf("Version: %s\n", index.Version)
+	fmt.Printf("Published: %s\n", index.PublishDate)
+	fmt.Printf("CCIs: %d\n", index.Len())
+	return nil
+}
+
+// showCCI handles the cci show command
+func showCCI(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex(cciMapPath())
+	if err != nil {
+		return err
+	}
+
+	id := strings.ToUpper(strings.TrimSpace(args[0]))
+	item, ok := index.Get(id)
+	if !ok {
+		return fmt.Errorf("%s is not in CCI list %s", id, index.Version)
+	}
+
+	fmt.Printf("%s\n", item.ID)
+	fmt.Printf("  Status: %s\n", item.Status)
+	fmt.Printf("  Type: %s\n", item.Type)
+	fmt.Printf("  Published: %s\n", item.PublishDate)
+	fmt.Printf("  Contributor: %s\n", item.Contributor)
+	fmt.Printf("  Definition: %s\n", strings.TrimSpace(item.Definition))
+	fmt.Println("  References:")
+	for _, ref := range item.References.References {
+		fmt.Printf("    - %s (version %s): %s\n", ref.Title, ref.Version, ref.Index)
+	}
+
+	fmt.Printf("  Control: %s\n", controlOrNone(index.ControlID(item.ID)))
+	if statementID := index.StatementID(item.ID); statementID != "" {
+		fmt.Printf("  Statement: %s\n", statementID)
+	}
+	if objectiveID := index.ObjectiveID(item.ID); objectiveID != "" {
+		fmt.Printf("  Assessment objective: %s\n", objectiveID)
+	}
+	return nil
+}
+
+// showControl handles the cci control command
+func showControl(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex(cciMapPath())
+	if err != nil {
+		return err
+	}
+
+	controlID := strings.ToLower(strings.TrimSpace(args[0]))
+	items := index.ByControl(controlID)
+	if len(items) == 0 {
+		return fmt.Errorf("no CCIs map to control %s", controlID)
+	}
+
+	fmt.Printf("%s: %d CCIs\n", controlID, len(items))
+	for _, item := range items {
+		fmt.Printf("  - %s: %s\n", item.ID, strings.TrimSpace(item.Definition))
+		if statementID := index.StatementID(item.ID); statementID != "" {
+			fmt.Printf("    Statement: %s\n", statementID)
+		}
+	}
+	return nil
+}
+
+// showCoverage handles the cci coverage command
+func showCoverage(cmd *cobra.Command, args []string) error {
+	inputPath := viper.GetString("cci.coverage.input")
+
+	checklist := &cklb.Checklist{}
+	if err := checklist.LoadFromFile(inputPath); err != nil {
+		return fmt.Errorf("error loading checklist: %v", err)
+	}
+
+	index, err := loadIndex(cciMapPath())
+	if err != nil {
+		return err
+	}
+
+	coverage := index.Coverage(checklist)
+	met := 0
+	for _, control := range coverage {
+		state := "not met"
+		if control.Met() {
+			state = "met"
+			met++
+		}
+		fmt.Printf("%s: %d of %d CCIs satisfied (%s)\n",
+			control.ControlID, control.Count(cciparsing.CoverageSatisfied), len(control.CCIs), state)
+		for _, cci := range control.CCIs {
+			if len(cci.Rules) > 0 {
+				fmt.Printf("  - %s: %s (%s)\n", cci.ID, cci.State, strings.Join(cci.Rules, ", "))
+			} else {
+				fmt.Printf("  - %s: %s\n", cci.ID, cci.State)
+			}
+		}
+	}
+
+	fmt.Printf("Controls met: %d of %d\n", met, len(coverage))
+
+	var ccis []string
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			ccis = append(ccis, rule.CCIs...)
+		}
+	}
+	if unmapped := index.Unmapped(ccis); len(unmapped) > 0 {
+		fmt.Printf("CCIs without a control: %s\n", strings.Join(unmapped, ", "))
+	}
+	return nil
+}
+
+// diffCCI handles the cci diff command
+func diffCCI(cmd *cobra.Command, args []string) error {
+	newPath := cciMapPath()
+	if len(args) > 1 {
+		newPath = args[1]
+	}
+
+	older, err := loadIndex(args[0])
+	if err != nil {
+		return err
+	}
+	newer, err := loadIndex(newPath)
+	if err != nil {
+		return err
+	}
+
+	diff := older.Diff(newer)
+	fmt.Printf("Comparing CCI list %s with %s\n", diff.OldVersion, diff.NewVersion)
+	if diff.Empty() {
+		fmt.Println("No differences")
+	}
+
+	if len(diff.Added) > 0 {
+		fmt.Printf("Added CCIs: %d\n", len(diff.Added))
+		for _, id := range diff.Added {
+			fmt.Printf("  + %s -> %s\n", id, controlOrNone(newer.ControlID(id)))
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("Removed CCIs: %d\n", len(diff.Removed))
+		for _, id := range diff.Removed {
+			fmt.Printf("  - %s (was %s)\n", id, controlOrNone(older.ControlID(id)))
+		}
+	}
+	if len(diff.DefinitionChanges) > 0 {
+		fmt.Printf("Changed definitions: %d\n", len(diff.DefinitionChanges))
+		for _, change := range diff.DefinitionChanges {
+			fmt.Printf("  ~ %s\n", change.ID)
+			if change.OldStatus != change.NewStatus {
+				fmt.Printf("    status: %s -> %s\n", change.OldStatus, change.NewStatus)
+			}
+			if change.Old != change.New {
+				fmt.Printf("    old: %s\n", change.Old)
+				fmt.Printf("    new: %s\n", change.New)
+			}
+		}
+	}
+	if len(diff.MappingChanges) > 0 {
+		fmt.Printf("Changed control mappings: %d\n", len(diff.MappingChanges))
+		for _, change := range diff.MappingChanges {
+			fmt.Printf("  ~ %s: %s -> %s\n", change.ID, controlOrNone(change.OldControl), controlOrNone(change.NewControl))
+		}
+	}
+
+	components, err := findComponentDefinitions(viper.GetStringSlice("cci.diff.components"))
+	if err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+
+	affectedCount := 0
+	for _, path := range components {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read component definition: %w", err)
+		}
+		if affected := diff.Affected(cciPattern.FindAllString(string(data), -1)); len(affected) > 0 {
+			affectedCount++
+			fmt.Printf("Affected component definition: %s\n", path)
+			fmt.Printf("  CCIs: %s\n", strings.Join(affected, ", "))
+		}
+	}
+	fmt.Printf("Affected component definitions: %d of %d\n", affectedCount, len(components))
+	return nil
+}
+
+// cciPattern matches CCI identifiers cited anywhere in a component definition
+var cciPattern = regexp.MustCompile(`CCI-\d{6}`)
+
+// findComponentDefinitions expands directories to the JSON files they contain
+func findComponentDefinitions(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(file), ".json") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func controlOrNone(controlID string) string {
+	if controlID == "" {
+		return "none"
+	}
+	return controlID
+}
+
+// cciMapPath returns the configured CCI list, from the --cci-map flag or the
+// cci.cciMap configuration, falling back to oscal.component.cciMap
+func cciMapPath() string {
+	if path := viper.GetString("cci.cciMap"); path != "" {
+		return path
+	}
+	return viper.GetString("oscal.component.cciMap")
+}
+
+// loadIndex loads a CCI list, or the imported or embedded one if path is empty, and maps it to
+// the selected NIST revision
+func loadIndex(path string) (*cciparsing.CCIIndex, error) {
+	index, err := cciparsing.LoadCCIIndex(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CCI document: %w", err)
+	}
+
+	preference, err := cciparsing.PreferenceForRevision(viper.GetString("cci.nistRevision"), !viper.GetBool("cci.noFallback"))
+	if err != nil {
+		return nil, err
+	}
+	index.SetPreference(preference)
+
+	return index, nil
+}
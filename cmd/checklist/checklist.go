@@ -0,0 +1,295 @@
+package checklist
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oval"
+	"github.com/open-automation-construct/oscalctl/internal/xccdf"
+)
+
+// formatXCCDFResults is the export format for XCCDF TestResult documents
+const formatXCCDFResults = "xccdf-results"
+
+// NewCmd creates a new checklist command
+func NewCmd() *cobra.Command {
+	checklistCmd := &cobra.Command{
+		Use:   "checklist",
+		Short: "Fill out and export STIG checklists",
+		Long:  `Fill out STIG checklists from automated content and export them to other formats.`,
+	}
+
+	checklistCmd.AddCommand(newEvaluateCmd())
+	checklistCmd.AddCommand(newExportCmd())
+
+	return checklistCmd
+}
+
+// newEvaluateCmd creates an evaluate subcommand
+func newEvaluateCmd() *cobra.Command {
+	evaluateCmd := &cobra.Command{
+		Use:   "evaluate",
+		Short: "Evaluate OVAL content against a host and fill out a checklist",
+		Long: `Evaluate the OVAL definitions of an automated STIG benchmark against the local
+host or a mounted root filesystem, and record the results in a CKLB checklist.
+When the benchmark is a SCAP data stream collection, its embedded OVAL content
+is used unless --oval is given.
+
+Only the textfilecontent54, file, family, rpminfo, dpkginfo and sysctl tests are
+supported. Rules whose definitions cannot be evaluated keep their status.`,
+		RunE: evaluateChecklist,
+	}
+
+	// Add flags
+	evaluateCmd.Flags().String("oval", "", "Path to the OVAL definitions (defaults to the OVAL embedded in a data stream benchmark)")
+	evaluateCmd.Flags().StringP("input", "i", "", "Path to an existing checklist to update")
+	evaluateCmd.Flags().StringP("benchmark", "b", "", "Path to the XCCDF benchmark to create a new checklist from")
+	evaluateCmd.Flags().String("root", "/", "Root filesystem to evaluate, for example a mounted image")
+	evaluateCmd.Flags().StringP("output", "o", "", "Path to the output checklist (required)")
+
+	// Bind flags to viper
+	if err := viper.BindPFlag("checklist.evaluate.oval", evaluateCmd.Flags().Lookup("oval")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.evaluate.input", evaluateCmd.Flags().Lookup("input")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.evaluate.benchmark", evaluateCmd.Flags().Lookup("benchmark")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.evaluate.root", evaluateCmd.Flags().Lookup("root")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.evaluate.output", evaluateCmd.Flags().Lookup("output")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+
+	// Mark required flags
+	if err := evaluateCmd.MarkFlagRequired("output"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return evaluateCmd
+}
+
+// newExportCmd creates an export subcommand
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a checklist to another format",
+		Long: `Export a CKLB checklist to another format.
+
+The xccdf-results format writes an XCCDF 1.2 TestResult document with one
+rule-result per checklist rule. Checklists holding several STIGs produce one
+document per STIG, named after the output path with the STIG id appended.`,
+		RunE: exportChecklist,
+	}
+
+	// Add flags
+	exportCmd.Flags().StringP("input", "i", "", "Path to the checklist (required)")
+	exportCmd.Flags().StringP("output", "o", "", "Path to the exported document (required)")
+	exportCmd.Flags().String("format", formatXCCDFResults, "Export format: xccdf-results")
+
+	// Bind flags to viper
+	if err := viper.BindPFlag("checklist.export.input", exportCmd.Flags().Lookup("input")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.export.output", exportCmd.Flags().Lookup("output")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("checklist.export.format", exportCmd.Flags().Lookup("format")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+
+	// Mark required flags
+	for _, flag := range []string{"input", "output"} {
+		if err := exportCmd.MarkFlagRequired(flag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+		}
+	}
+
+	return exportCmd
+}
+
+// evaluateChecklist handles the checklist evaluate command
+func evaluateChecklist(cmd *cobra.Command, args []string) error {
+	ovalPath := viper.GetString("checklist.evaluate.oval")
+	inputPath := viper.GetString("checklist.evaluate.input")
+	benchmarkPath := viper.GetString("checklist.evaluate.benchmark")
+	root := viper.GetString("checklist.evaluate.root")
+	outputPath := viper.GetString("checklist.evaluate.output")
+
+	checklist, err := loadChecklist(inputPath, benchmarkPath)
+	if err != nil {
+		return err
+	}
+
+	definitions, err := loadDefinitions(ovalPath, benchmarkPath)
+	if err != nil {
+		return fmt.Errorf("failed to load OVAL definitions: %w", err)
+	}
+
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("root filesystem is not a directory: %s", root)
+	}
+
+	results := make(map[string]oval.DefinitionResult)
+	for _, d := range definitions {
+		fmt.Printf("Evaluating %d OVAL definitions against %s\n", len(d.Definitions), root)
+		for id, result := range oval.NewEvaluator(d, root).EvaluateAll() {
+			results[id] = result
+		}
+	}
+	summary := oval.ApplyResults(checklist, results)
+
+	if checklist.Data.TargetData.HostName == "" {
+		if hostname, err := os.ReadFile(filepath.Join(root, "etc", "hostname")); err == nil {
+			checklist.Data.TargetData.HostName = strings.TrimSpace(string(hostname))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := checklist.SaveToFile(outputPath); err != nil {
+		return fmt.Errorf("failed to write checklist: %w", err)
+	}
+
+	fmt.Printf("Evaluated rules: %d\n", summary.Evaluated)
+	fmt.Printf("  not_a_finding: %d\n", summary.NotAFinding)
+	fmt.Printf("  open: %d\n", summary.Open)
+	fmt.Printf("  undetermined: %d\n", summary.Undetermined)
+	fmt.Printf("Successfully wrote checklist: %s\n", outputPath)
+	return nil
+}
+
+// exportChecklist handles the checklist export command
+func exportChecklist(cmd *cobra.Command, args []string) error {
+	inputPath := viper.GetString("checklist.export.input")
+	outputPath := viper.GetString("checklist.export.output")
+	format := viper.GetString("checklist.export.format")
+
+	if format != formatXCCDFResults {
+		return fmt.Errorf("unsupported format %q, expected %s", format, formatXCCDFResults)
+	}
+
+	checklist, err := loadChecklist(inputPath, "")
+	if err != nil {
+		return err
+	}
+
+	documents := xccdf.NewResultsDocuments(checklist, time.Now())
+	if len(documents) == 0 {
+		return fmt.Errorf("checklist %s contains no STIGs", inputPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for i, document := range documents {
+		path := outputPath
+		if len(documents) > 1 {
+			ext := filepath.Ext(outputPath)
+			path = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(outputPath, ext), checklist.Data.STIGs[i].STIGID, ext)
+		}
+		if err := writeResults(document, path); err != nil {
+			return fmt.Errorf("failed to write XCCDF results: %w", err)
+		}
+		fmt.Printf("Successfully exported %d rule results: %s\n", len(document.TestResult.RuleResults), path)
+	}
+
+	return nil
+}
+
+func writeResults(document *xccdf.ResultsDocument, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	return document.Write(file)
+}
+
+// loadChecklist loads an existing checklist or creates one from a benchmark
+func loadChecklist(inputPath, benchmarkPath string) (*cklb.Checklist, error) {
+	switch {
+	case inputPath != "":
+		checklist := &cklb.Checklist{}
+		if err := checklist.LoadFromFile(inputPath); err != nil {
+			return nil, fmt.Errorf("error loading checklist: %v", err)
+		}
+		return checklist, nil
+	case benchmarkPath != "":
+		benchmark, err := xccdf.LoadFromFile(benchmarkPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading XCCDF benchmark: %v", err)
+		}
+		return benchmark.ToChecklist(), nil
+	default:
+		return nil, fmt.Errorf("either --input or --benchmark is required")
+	}
+}
+
+// loadDefinitions loads the OVAL definitions from a file, or from the data
+// stream collection holding the benchmark
+func loadDefinitions(ovalPath, benchmarkPath string) ([]*oval.Definitions, error) {
+	if ovalPath != "" {
+		definitions, err := oval.LoadFromFile(ovalPath)
+		if err != nil {
+			return nil, err
+		}
+		return []*oval.Definitions{definitions}, nil
+	}
+
+	if benchmarkPath == "" {
+		return nil, fmt.Errorf("--oval is required unless --benchmark is a data stream collection")
+	}
+	data, err := os.ReadFile(benchmarkPath)
+	if err != nil {
+		return nil, err
+	}
+	if !xccdf.IsDataStreamCollection(data) {
+		return nil, fmt.Errorf("--oval is required unless --benchmark is a data stream collection")
+	}
+
+	collection, err := xccdf.ParseDataStreamCollection(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	benchmark, err := collection.Benchmark("")
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []*oval.Definitions
+	for _, href := range benchmark.Benchmark.CheckHrefs(oval.Namespace) {
+		content, err := collection.CheckContent(benchmark, href)
+		if err != nil {
+			return nil, err
+		}
+		d, err := oval.ParseReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", href, err)
+		}
+		definitions = append(definitions, d)
+	}
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("benchmark %s has no OVAL checks", benchmark.Benchmark.ID)
+	}
+
+	return definitions, nil
+}
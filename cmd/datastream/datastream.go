@@ -0,0 +1,200 @@
+package datastream
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/open-automation-construct/oscalctl/internal/oval"
+	"github.com/open-automation-construct/oscalctl/internal/xccdf"
+)
+
+// NewCmd creates a new datastream command
+func NewCmd() *cobra.Command {
+	datastreamCmd := &cobra.Command{
+		Use:   "datastream",
+		Short: "Read SCAP source data stream collections",
+		Long: `Read SCAP 1.2 and 1.3 source data stream collections, which bundle XCCDF,
+OVAL, OCIL and CPE content in a single document.
+
+Every command that accepts an XCCDF benchmark also accepts a data stream collection.`,
+	}
+
+	datastreamCmd.AddCommand(newListCmd())
+	datastreamCmd.AddCommand(newExtractCmd())
+
+	return datastreamCmd
+}
+
+// newListCmd creates a list subcommand
+func newListCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the benchmarks and profiles in a data stream collection",
+		RunE:  listDataStream,
+	}
+
+	listCmd.Flags().StringP("input", "i", "", "Path to the data stream collection (required)")
+
+	if err := viper.BindPFlag("datastream.list.input", listCmd.Flags().Lookup("input")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := listCmd.MarkFlagRequired("input"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+	}
+
+	return listCmd
+}
+
+// newExtractCmd creates an extract subcommand
+func newExtractCmd() *cobra.Command {
+	extractCmd := &cobra.Command{
+		Use:   "extract",
+		Short: "Extract a benchmark and its OVAL content from a data stream collection",
+		RunE:  extractDataStream,
+	}
+
+	extractCmd.Flags().StringP("input", "i", "", "Path to the data stream collection (required)")
+	extractCmd.Flags().StringP("benchmark", "b", "", "Benchmark id or component reference id (defaults to the first benchmark)")
+	extractCmd.Flags().StringP("output", "o", "", "Path to write the XCCDF benchmark (required)")
+	extractCmd.Flags().String("oval-output", "", "Path to write the benchmark's OVAL definitions, suffixed with each check href when there are several (optional)")
+
+	if err := viper.BindPFlag("datastream.extract.input", extractCmd.Flags().Lookup("input")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("datastream.extract.benchmark", extractCmd.Flags().Lookup("benchmark")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("datastream.extract.output", extractCmd.Flags().Lookup("output")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("datastream.extract.ovalOutput", extractCmd.Flags().Lookup("oval-output")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	for _, flag := range []string{"input", "output"} {
+		if err := extractCmd.MarkFlagRequired(flag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+		}
+	}
+
+	return extractCmd
+}
+
+// listDataStream handles the datastream list command
+func listDataStream(cmd *cobra.Command, args []string) error {
+	inputPath := viper.GetString("datastream.list.input")
+
+	collection, err := xccdf.LoadDataStreamCollection(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load data stream collection: %w", err)
+	}
+
+	benchmarks, err := collection.Benchmarks()
+	if err != nil {
+		return fmt.Errorf("failed to read benchmarks: %w", err)
+	}
+
+	fmt.Printf("Collection: %s\n", collection.ID)
+	for _, stream := range collection.DataStreams {
+		fmt.Printf("Data stream: %s (SCAP %s, %s)\n", stream.ID, stream.ScapVersion, stream.UseCase)
+
+		for _, b := range benchmarks {
+			if b.DataStreamID != stream.ID {
+				continue
+			}
+			fmt.Printf("  Benchmark: %s\n", b.Benchmark.ID)
+			fmt.Printf("    Title: %s\n", b.Benchmark.Title)
+			fmt.Printf("    Release: %s\n", b.Benchmark.ReleaseInfo())
+			fmt.Printf("    Component reference: %s\n", b.Ref.ID)
+			fmt.Printf("    Rules: %d\n", len(b.Benchmark.GetRules()))
+			if len(b.Benchmark.Profiles) > 0 {
+				fmt.Println("    Profiles:")
+				for _, profile := range b.Benchmark.Profiles {
+					fmt.Printf("      - %s: %s\n", profile.ID, profile.Title)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// extractDataStream handles the datastream extract command
+func extractDataStream(cmd *cobra.Command, args []string) error {
+	inputPath := viper.GetString("datastream.extract.input")
+	benchmarkID := viper.GetString("datastream.extract.benchmark")
+	outputPath := viper.GetString("datastream.extract.output")
+	ovalOutputPath := viper.GetString("datastream.extract.ovalOutput")
+
+	collection, err := xccdf.LoadDataStreamCollection(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to load data stream collection: %w", err)
+	}
+
+	benchmark, err := collection.Benchmark(benchmarkID)
+	if err != nil {
+		return err
+	}
+
+	content, err := collection.Document(collection.Component(benchmark.Ref.Href))
+	if err != nil {
+		return err
+	}
+	if err := writeFile(outputPath, content); err != nil {
+		return fmt.Errorf("failed to write benchmark: %w", err)
+	}
+	fmt.Printf("Successfully extracted benchmark %s: %s\n", benchmark.Benchmark.ID, outputPath)
+
+	if ovalOutputPath == "" {
+		return nil
+	}
+
+	hrefs := benchmark.Benchmark.CheckHrefs(oval.Namespace)
+	if len(hrefs) == 0 {
+		return fmt.Errorf("benchmark %s has no OVAL checks", benchmark.Benchmark.ID)
+	}
+	written := make(map[string]string)
+	for _, href := range hrefs {
+		content, err := collection.CheckContent(benchmark, href)
+		if err != nil {
+			return err
+		}
+		path := ovalOutputPath
+		if len(hrefs) > 1 {
+			path = ovalPath(ovalOutputPath, href)
+		}
+		if other, ok := written[path]; ok {
+			return fmt.Errorf("OVAL definitions %s and %s would both be written to %s", other, href, path)
+		}
+		written[path] = href
+		if err := writeFile(path, content); err != nil {
+			return fmt.Errorf("failed to write OVAL definitions: %w", err)
+		}
+		fmt.Printf("Successfully extracted OVAL definitions %s: %s\n", href, path)
+	}
+
+	return nil
+}
+
+// ovalPath names the output of one of several OVAL documents after the output path and the check href
+func ovalPath(outputPath, href string) string {
+	ext := filepath.Ext(outputPath)
+	name := filepath.Base(strings.TrimPrefix(href, "#"))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSuffix(outputPath, ext) + "-" + name + ext
+}
+
+// writeFile writes an embedded component as a standalone XML document
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	document := append([]byte(xml.Header), bytes.TrimSpace(content)...)
+	return os.WriteFile(path, document, 0644)
+}
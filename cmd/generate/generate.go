@@ -2,10 +2,12 @@ package generate
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
 	"github.com/open-automation-construct/oscalctl/internal/cklb"
 	"github.com/open-automation-construct/oscalctl/cmd/generate/oscal"
 )
@@ -51,7 +53,17 @@ checklists or generating new ones based on templates, or converting them to othe
 			fmt.Printf("ID: %s\n", checklist.Data.ID)
 			fmt.Printf("Number of STIGs: %d\n", len(checklist.Data.STIGs))
 			
-			return nil
+			// Flag rules citing deprecated or draft CCIs
+			cciPath := cciMapPath()
+			cciIndex, err := cciparsing.LoadCCIIndex(cciPath)
+			if err != nil {
+				if cciPath != "" {
+					return fmt.Errorf("error loading CCI list: %v", err)
+				}
+				fmt.Printf("Skipping CCI status check: %v\n", err)
+				return nil
+			}
+			return cciIndex.ReportStatuses(os.Stdout, checklist, viper.GetBool("generate.quietDraft"), viper.GetBool("generate.failOnDeprecatedCCI"))
 		},
 	}
 
@@ -60,12 +72,35 @@ checklists or generating new ones based on templates, or converting them to othe
 	
 	// Add flags for the generate command
 	generateCmd.Flags().StringP("cklbFile", "f", "", "Path of the checklist file to process")
+	generateCmd.Flags().String("cci-map", "", "Path to a custom CCI XML document (optional, uses the configured, imported or embedded CCI list if not specified)")
+	generateCmd.Flags().Bool("fail-on-deprecated-cci", false, "Fail if a rule cites a deprecated CCI")
+	generateCmd.Flags().Bool("quiet-draft", false, "Only count the rules citing draft CCIs instead of listing them")
 
 	if err := viper.BindPFlag("cklbFile", generateCmd.Flags().Lookup("cklbFile")); err != nil {
 		// Handle the error - in a function that can't return an error, we can panic or log
 		// Since this is part of initialization, panicking is reasonable
 		panic(fmt.Sprintf("Failed to bind flag 'cklbFile': %v", err))
 	}
+	if err := viper.BindPFlag("generate.cciMap", generateCmd.Flags().Lookup("cci-map")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("generate.failOnDeprecatedCCI", generateCmd.Flags().Lookup("fail-on-deprecated-cci")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("generate.quietDraft", generateCmd.Flags().Lookup("quiet-draft")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
 
 	return generateCmd
+}
+
+// cciMapPath returns the CCI list to check the checklist against: the --cci-map
+// flag or generate.cciMap, falling back to cci.cciMap and oscal.component.cciMap
+func cciMapPath() string {
+	for _, key := range []string{"generate.cciMap", "cci.cciMap", "oscal.component.cciMap"} {
+		if path := viper.GetString(key); path != "" {
+			return path
+		}
+	}
+	return ""
 }
\ No newline at end of file
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
 	"github.com/open-automation-construct/oscalctl/internal/oscal/component"
 )
 
@@ -47,7 +49,30 @@ that can be used with OSCAL-compatible tools.`,
 	// Add flags
 	componentCmd.Flags().StringP("input", "i", "", "Path to the STIG checklist (required)")
 	componentCmd.Flags().StringP("output", "o", "", "Path to the output OSCAL component definition (required)")
-	componentCmd.Flags().String("cci-map", "", "Path to a custom CCI XML document (optional, uses embedded CCI list if not specified)")
+	componentCmd.Flags().String("cci-map", "", "Path to a custom CCI XML document (optional, uses the imported or embedded CCI list if not specified)")
+	componentCmd.Flags().String("nist-revision", "5", "NIST SP 800-53 revision to map CCIs to: 4 or 5")
+	componentCmd.Flags().Bool("no-fallback", false, "Do not fall back to other NIST references for CCIs without a reference to the selected revision")
+	componentCmd.Flags().Bool("fail-on-deprecated-cci", false, "Fail if a rule cites a deprecated CCI")
+	componentCmd.Flags().Bool("quiet-draft", false, "Only count the rules citing draft CCIs instead of listing them")
+	componentCmd.Flags().String("cci-overrides", "", "Path to a CSV file with cci and control columns overriding the CCI list mapping (optional)")
+	componentCmd.Flags().String("framework", "800-53", "Framework to key implemented requirements to, such as 800-171 (requires a crosswalk)")
+	componentCmd.Flags().String("crosswalk", "", "Path to a crosswalk from NIST SP 800-53 to the framework, as OSCAL mapping JSON or CSV")
+	componentCmd.Flags().String("catalog", "", "Path to an OSCAL 800-53 catalog in JSON used to check control and statement ids (optional, uses the embedded catalog if not specified)")
+	componentCmd.Flags().Bool("fail-on-unresolved-control", false, "Fail if a control or statement id is not in the catalog")
+	componentCmd.Flags().String("profile", "", "Path to an OSCAL 800-53 baseline profile in JSON that control implementations refer to (optional)")
+	componentCmd.Flags().String("out-of-baseline", component.OutOfBaselineTag, "What to do with requirements of controls outside the --profile baseline: "+strings.Join(component.OutOfBaselinePolicies, ", "))
+	componentCmd.Flags().String("baseline-report", "", "Path of the report of baseline controls no rule covers with --profile (default <output>-baseline-gaps.csv)")
+	componentCmd.Flags().String("unmapped", component.UnmappedOmit, "What to do with rules that map to no control: "+strings.Join(component.UnmappedPolicies, ", "))
+	componentCmd.Flags().String("unmapped-control", "", "Catch-all control id for unmapped rules with --unmapped control")
+	componentCmd.Flags().String("unmapped-report", "", "Path of the unmapped rule report with --unmapped report (default <output>-unmapped.csv)")
+	componentCmd.Flags().StringToString("status-map", nil, "Map checklist statuses to implementation statuses, such as open=partial (defaults: not_a_finding=implemented, open=not-implemented, not_applicable=not-applicable, not_reviewed=planned)")
+	componentCmd.Flags().Bool("capability", false, "Group the components of the checklist's STIGs under a capability named after the checklist")
+	componentCmd.Flags().Bool("reproducible", false, "Derive UUIDs and the last-modified time from the checklist so identical inputs give identical output")
+	componentCmd.Flags().String("component-type", "", "Type of the generated components: "+strings.Join(component.ComponentTypes, ", ")+" (default software)")
+	componentCmd.Flags().String("purpose", "", "Purpose of the generated components (optional)")
+	componentCmd.Flags().String("document-version", "", "Version of the component definition document (default 1.0.0)")
+	componentCmd.Flags().String("metadata", "", "Path to a YAML file with the component type, purpose, protocols, responsible roles, parties, roles and locations (optional)")
+	componentCmd.Flags().Bool("rollup-enhancements", false, "Aggregate control enhancements, such as ac-2.3, under their base control")
 
 	// Bind flags to viper
 	if err := viper.BindPFlag("oscal.component.input", componentCmd.Flags().Lookup("input")); err != nil {
@@ -59,6 +84,75 @@ that can be used with OSCAL-compatible tools.`,
 	if err := viper.BindPFlag("oscal.component.cciMap", componentCmd.Flags().Lookup("cci-map")); err != nil {
 		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
 	}
+	if err := viper.BindPFlag("oscal.component.nistRevision", componentCmd.Flags().Lookup("nist-revision")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.noFallback", componentCmd.Flags().Lookup("no-fallback")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.failOnDeprecatedCCI", componentCmd.Flags().Lookup("fail-on-deprecated-cci")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.quietDraft", componentCmd.Flags().Lookup("quiet-draft")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.profile", componentCmd.Flags().Lookup("profile")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.outOfBaseline", componentCmd.Flags().Lookup("out-of-baseline")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.baselineReport", componentCmd.Flags().Lookup("baseline-report")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.unmappedPolicy", componentCmd.Flags().Lookup("unmapped")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.unmappedControl", componentCmd.Flags().Lookup("unmapped-control")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.unmappedReport", componentCmd.Flags().Lookup("unmapped-report")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.statusMapping", componentCmd.Flags().Lookup("status-map")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.capability", componentCmd.Flags().Lookup("capability")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.reproducible", componentCmd.Flags().Lookup("reproducible")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.type", componentCmd.Flags().Lookup("component-type")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.purpose", componentCmd.Flags().Lookup("purpose")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.version", componentCmd.Flags().Lookup("document-version")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.metadataFile", componentCmd.Flags().Lookup("metadata")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.rollupEnhancements", componentCmd.Flags().Lookup("rollup-enhancements")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.cciOverridesFile", componentCmd.Flags().Lookup("cci-overrides")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.framework", componentCmd.Flags().Lookup("framework")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.crosswalk", componentCmd.Flags().Lookup("crosswalk")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.failOnUnresolvedControl", componentCmd.Flags().Lookup("fail-on-unresolved-control")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("oscal.component.catalog", componentCmd.Flags().Lookup("catalog")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
 
 	// Mark required flags
 	if err := componentCmd.MarkFlagRequired("input"); err != nil {
@@ -96,7 +190,31 @@ func generateOSCALComponent(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("Using custom CCI mapping file: %s\n", cciPath)
 	} else {
-		fmt.Println("Using embedded CCI mapping file")
+		fmt.Printf("Using %s\n", cciparsing.ResolveCCISource(""))
+	}
+
+	// Verify the catalog exists if specified
+	if catalogPath := viper.GetString("oscal.component.catalog"); catalogPath != "" {
+		if _, err := os.Stat(catalogPath); os.IsNotExist(err) {
+			return fmt.Errorf("specified catalog does not exist: %s", catalogPath)
+		}
+		fmt.Printf("Checking control and statement ids against catalog: %s\n", catalogPath)
+	}
+
+	// Verify the profile exists if specified
+	if profilePath := viper.GetString("oscal.component.profile"); profilePath != "" {
+		if _, err := os.Stat(profilePath); os.IsNotExist(err) {
+			return fmt.Errorf("specified profile does not exist: %s", profilePath)
+		}
+		fmt.Printf("Scoping control implementations to profile: %s\n", profilePath)
+	}
+
+	// Verify the metadata file exists if specified
+	if metadataPath := viper.GetString("oscal.component.metadataFile"); metadataPath != "" {
+		if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
+			return fmt.Errorf("specified metadata file does not exist: %s", metadataPath)
+		}
+		fmt.Printf("Using component metadata file: %s\n", metadataPath)
 	}
 
 	// Display title if specified
@@ -13,7 +13,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	
+	"github.com/open-automation-construct/oscalctl/cmd/cci"
+	"github.com/open-automation-construct/oscalctl/cmd/checklist"
+	"github.com/open-automation-construct/oscalctl/cmd/datastream"
 	"github.com/open-automation-construct/oscalctl/cmd/generate"
+	"github.com/open-automation-construct/oscalctl/cmd/srg"
 )
 
 
@@ -51,6 +55,10 @@ func init() {
 
 	generateCmd := generate.NewCmd()
     rootCmd.AddCommand(generateCmd)
+    rootCmd.AddCommand(srg.NewCmd())
+    rootCmd.AddCommand(checklist.NewCmd())
+    rootCmd.AddCommand(datastream.NewCmd())
+    rootCmd.AddCommand(cci.NewCmd())
 
     cobra.OnInitialize(func() {
         if err := initializeConfig(rootCmd); err != nil {
@@ -0,0 +1,126 @@
+package srg
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/open-automation-construct/oscalctl/internal/srg"
+	"github.com/open-automation-construct/oscalctl/internal/xccdf"
+)
+
+// NewCmd creates a new SRG command
+func NewCmd() *cobra.Command {
+	srgCmd := &cobra.Command{
+		Use:   "srg",
+		Short: "Work with DISA Security Requirements Guides",
+		Long:  `Work with DISA Security Requirements Guides (SRGs), such as tracing STIG rules back to SRG requirements.`,
+	}
+
+	srgCmd.AddCommand(newTraceCmd())
+
+	return srgCmd
+}
+
+// newTraceCmd creates a trace subcommand
+func newTraceCmd() *cobra.Command {
+	traceCmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Report which SRG requirements a STIG implements",
+		Long: `Report which SRG requirements each STIG rule implements and which SRG
+requirements have no STIG coverage. Both the SRG and the STIG can be an XCCDF
+benchmark (.xml) or a CKLB checklist.`,
+		RunE: traceSRG,
+	}
+
+	// Add flags
+	traceCmd.Flags().String("srg", "", "Path to the SRG XCCDF benchmark or checklist (required)")
+	traceCmd.Flags().String("stig", "", "Path to the STIG XCCDF benchmark or checklist (required)")
+	traceCmd.Flags().StringP("output", "o", "", "Path to write the traceability report (optional)")
+	traceCmd.Flags().String("format", srg.FormatCSV, "Report format: csv or oscal")
+
+	// Bind flags to viper
+	if err := viper.BindPFlag("srg.trace.srg", traceCmd.Flags().Lookup("srg")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("srg.trace.stig", traceCmd.Flags().Lookup("stig")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("srg.trace.output", traceCmd.Flags().Lookup("output")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("srg.trace.format", traceCmd.Flags().Lookup("format")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding flag: %v\n", err)
+	}
+
+	// Mark required flags
+	for _, flag := range []string{"srg", "stig"} {
+		if err := traceCmd.MarkFlagRequired(flag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marking flag as required: %v\n", err)
+		}
+	}
+
+	return traceCmd
+}
+
+// traceSRG handles the srg trace command
+func traceSRG(cmd *cobra.Command, args []string) error {
+	srgPath := viper.GetString("srg.trace.srg")
+	stigPath := viper.GetString("srg.trace.stig")
+	outputPath := viper.GetString("srg.trace.output")
+	format := viper.GetString("srg.trace.format")
+
+	if format != srg.FormatCSV && format != srg.FormatOSCAL {
+		return fmt.Errorf("unsupported format %q, expected %s or %s", format, srg.FormatCSV, srg.FormatOSCAL)
+	}
+
+	srgs, err := xccdf.LoadSTIGs(srgPath)
+	if err != nil {
+		return fmt.Errorf("failed to load SRG: %w", err)
+	}
+	stigs, err := xccdf.LoadSTIGs(stigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load STIG: %w", err)
+	}
+
+	trace := srg.BuildTrace(srgs, stigs)
+
+	covered, total := trace.Coverage()
+	fmt.Printf("SRG: %s\n", trace.SRGTitle)
+	fmt.Printf("STIG: %s\n", trace.STIGTitle)
+	fmt.Printf("Covered SRG requirements: %d of %d\n", covered, total)
+
+	if uncovered := trace.Uncovered(); len(uncovered) > 0 {
+		fmt.Println("SRG requirements without STIG coverage:")
+		for _, req := range uncovered {
+			fmt.Printf("  - %s: %s\n", req.ID, req.Title)
+		}
+	}
+	baseIDMatches := make(map[string]bool)
+	for _, mapping := range trace.Mappings {
+		for _, impl := range mapping.Implementations {
+			if impl.BaseIDMatch {
+				baseIDMatches[impl.STIGID+" "+impl.RuleID] = true
+			}
+		}
+	}
+	if len(baseIDMatches) > 0 {
+		fmt.Printf("STIG rules matched by base SRG id only: %d\n", len(baseIDMatches))
+	}
+	if len(trace.Unmatched) > 0 {
+		fmt.Printf("STIG rules citing SRG ids outside the SRG: %d\n", len(trace.Unmatched))
+	}
+
+	if outputPath == "" {
+		return nil
+	}
+
+	if err := srg.WriteReport(trace, format, srgPath, outputPath); err != nil {
+		return fmt.Errorf("failed to write traceability report: %w", err)
+	}
+
+	fmt.Printf("Successfully wrote traceability report: %s\n", outputPath)
+	return nil
+}
@@ -4,19 +4,26 @@ import (
 	"embed"
 	"encoding/xml"
 	"io"
-	"os"
-	"fmt"
 	"regexp"
 	"strings"
 )
 
-//go:embed assets/cci_list.xml
+// embeddedFS holds the CCI list and, once go generate has run, its precompiled index
+//
+//go:embed assets
 var embeddedFS embed.FS
 
 // CCIDocument represents the structure of the CCI XML document
 type CCIDocument struct {
-	XMLName  xml.Name  `xml:"cci_list"`
-	CCIItems []CCIItem `xml:"cci_items>cci_item"`
+	XMLName  xml.Name    `xml:"cci_list"`
+	Metadata CCIMetadata `xml:"metadata"`
+	CCIItems []CCIItem   `xml:"cci_items>cci_item"`
+}
+
+// CCIMetadata represents the version information of the CCI list
+type CCIMetadata struct {
+	Version     string `xml:"version"`
+	PublishDate string `xml:"publishdate"`
 }
 
 // CCIItem represents a single CCI item in the document
@@ -44,11 +51,13 @@ type Reference struct {
 	Index     string `xml:"index,attr"`
 }
 
+// controlPattern matches the base control and enhancement of a reference index
+var controlPattern = regexp.MustCompile(`([A-Z]+-\d+)\s*(?:\((\d+)\))?`)
+
 // normalizeControlID converts control IDs like 'SA-4 (7) (a)' to OSCAL format like 'sa-4.7'
 func normalizeControlID(index string) string {
 	// Match base control and enhancement
-	re := regexp.MustCompile(`([A-Z]+-\d+)\s*(?:\((\d+)\))?`)
-	matches := re.FindStringSubmatch(index)
+	matches := controlPattern.FindStringSubmatch(index)
 	
 	if len(matches) < 2 {
 		return ""
@@ -63,39 +72,77 @@ func normalizeControlID(index string) string {
 	return base
 }
 
-// getPreferredControlID finds the most relevant control ID based on preference order
-func getPreferredControlID(references []Reference) string {
-	// Define preference order for references
-	preferenceOrder := []string{
-		"NIST SP 800-53 Revision 5",
-		"NIST SP 800-53 Revision 4",
-		"NIST SP 800-53",
-		"NIST SP 800-53A",
+// statementPattern splits a reference index like 'AC-2 a 1' or 'SA-4 (7) (a)'
+// into its control, enhancement and statement parts
+var statementPattern = regexp.MustCompile(`^\s*([A-Z]+-\d+)\s*(?:\((\d+)\))?(.*)$`)
+
+// statementTokenPattern matches a single statement part such as 'a', '1' or '(a)'
+var statementTokenPattern = regexp.MustCompile(`^\(?([A-Za-z0-9]+)\)?\.?$`)
+
+// normalizeStatementID converts reference indexes like 'AC-2 a 1' to OSCAL statement
+// ids like 'ac-2_smt.a.1'. It returns "" if the index names no statement part.
+func normalizeStatementID(index string) string {
+	matches := statementPattern.FindStringSubmatch(index)
+	if matches == nil {
+		return ""
 	}
 	
-	var selectedRef *Reference
+	controlID := strings.ToLower(matches[1])
+	if matches[2] != "" {
+		controlID += "." + matches[2]
+	}
 	
-	// Find the highest preference reference
-	for _, pref := range preferenceOrder {
-		for i := range references {
-			if references[i].Title == pref {
-				selectedRef = &references[i]
-				break
-			}
-		}
-		if selectedRef != nil {
-			break
+	fields := strings.Fields(matches[3])
+	if len(fields) == 0 {
+		return ""
+	}
+	
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		token := statementTokenPattern.FindStringSubmatch(field)
+		if token == nil {
+			return ""
 		}
+		parts = append(parts, strings.ToLower(token[1]))
 	}
 	
+	return controlID + "_smt." + strings.Join(parts, ".")
+}
+
+// getPreferredControlID finds the most relevant control ID based on the default preference order
+func getPreferredControlID(references []Reference) string {
 	// If a reference was found, normalize the index to OSCAL format
-	if selectedRef != nil {
+	if selectedRef := getPreferredReference(references, DefaultPreference); selectedRef != nil {
 		return normalizeControlID(selectedRef.Index)
 	}
 	
 	return ""
 }
 
+// getPreferredReference finds the most relevant reference based on preference order
+func getPreferredReference(references []Reference, preference []ReferencePreference) *Reference {
+	// Find the highest preference reference
+	for _, pref := range preference {
+		for i := range references {
+			if pref.Matches(references[i]) {
+				return &references[i]
+			}
+		}
+	}
+	
+	return nil
+}
+
+// statementIDForReference returns the OSCAL statement ID named by a reference.
+// Assessment procedure references (800-53A) do not name statements.
+func statementIDForReference(reference *Reference) string {
+	if reference == nil || reference.Title == "NIST SP 800-53A" {
+		return ""
+	}
+	
+	return normalizeStatementID(reference.Index)
+}
+
 // IsValidOSCALToken checks if a string conforms to the OSCAL token pattern
 // pattern: "^(\\p{L}|_)(\\p{L}|\\p{N}|[.\\-_])*$"
 func IsValidOSCALToken(s string) bool {
@@ -126,60 +173,31 @@ func IsValidOSCALToken(s string) bool {
 
 // GetEmbeddedCCIControlMap parses the embedded CCI XML document
 func GetEmbeddedCCIControlMap() (map[string]string, error) {
-	file, err := embeddedFS.Open("assets/cci_list.xml")
+	index, err := GetEmbeddedCCIIndex()
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
-		}
-	}()
 	
-	return ParseCCIDocumentReader(file)
+	return index.ControlMap(), nil
 }
 
 // ParseCCIDocument parses a CCI XML document from a file path and returns a map of CCI IDs to control IDs
-// If filePath is empty, uses the embedded CCI document
+// If filePath is empty, uses the imported CCI list, or the embedded one if none was imported
 func ParseCCIDocument(filePath string) (map[string]string, error) {
-	// If no file path is provided, use the embedded CCI list
-	if filePath == "" {
-		return GetEmbeddedCCIControlMap()
-	}
-	
-	// Otherwise, read from the specified file
-	file, err := os.Open(filePath)
+	index, err := LoadCCIIndex(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
-		}
-	}()
 	
-	return ParseCCIDocumentReader(file)
+	return index.ControlMap(), nil
 }
 
 // ParseCCIDocumentReader parses a CCI XML document from a reader
 func ParseCCIDocumentReader(reader io.Reader) (map[string]string, error) {
-	var document CCIDocument
-	decoder := xml.NewDecoder(reader)
-	
-	if err := decoder.Decode(&document); err != nil {
+	index, err := ParseCCIIndexReader(reader)
+	if err != nil {
 		return nil, err
 	}
 	
-	result := make(map[string]string)
-	
-	for _, item := range document.CCIItems {
-		controlID := getPreferredControlID(item.References.References)
-		
-		// Only include control IDs that match the OSCAL token pattern
-		if controlID != "" && IsValidOSCALToken(controlID) {
-			result[item.ID] = controlID
-		}
-	}
-	
-	return result, nil
+	return index.ControlMap(), nil
 }
\ No newline at end of file
@@ -1,8 +1,15 @@
 package cciparsing
 
 import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
 )
 
 func TestNormalizeControlID(t *testing.T) {
@@ -24,6 +31,28 @@ func TestNormalizeControlID(t *testing.T) {
 	}
 }
 
+func TestNormalizeStatementID(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"AC-2 a 1", "ac-2_smt.a.1"},
+		{"SA-4 (7) (a)", "sa-4.7_smt.a"},
+		{"CM-6 b", "cm-6_smt.b"},
+		{"AC-2 (4)", ""},
+		{"AC-2", ""},
+		{"AC-2 (1).1", ""},
+		{"Invalid", ""},
+	}
+	
+	for _, tc := range testCases {
+		result := normalizeStatementID(tc.input)
+		if result != tc.expected {
+			t.Errorf("normalizeStatementID(%s) = %s, expected %s", tc.input, result, tc.expected)
+		}
+	}
+}
+
 func TestIsValidOSCALToken(t *testing.T) {
 	testCases := []struct {
 		input    string
@@ -126,4 +155,413 @@ func TestParseCCIDocumentReader(t *testing.T) {
 			t.Errorf("ParseCCIDocumentReader() result[%s] = %s, expected %s", k, result[k], v)
 		}
 	}
-}
\ No newline at end of file
+}
+const testIndexXML = `<?xml version="1.0" encoding="utf-8"?>
+<cci_list xmlns="http://iase.disa.mil/cci">
+  <metadata>
+    <version>2025-09-19</version>
+    <publishdate>2025-09-19</publishdate>
+  </metadata>
+  <cci_items>
+    <cci_item id="CCI-000015">
+      <status>draft</status>
+      <publishdate>2009-05-13</publishdate>
+      <contributor>DISA FSO</contributor>
+      <definition>Support the management of system accounts using organization-defined automated mechanisms.</definition>
+      <type>technical</type>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53" version="3" location="http://csrc.nist.gov/publications/PubsSPs.html" index="AC-2 (1)" />
+        <reference creator="NIST" title="NIST SP 800-53A" version="1" location="http://csrc.nist.gov/publications/PubsSPs.html" index="AC-2 (1).1" />
+        <reference creator="NIST" title="NIST SP 800-53 Revision 4" version="4" location="http://csrc.nist.gov/publications/PubsSPs.html" index="AC-2 (1)" />
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" location="https://csrc.nist.gov/pubs/sp/800/53/r5/upd1/final" index="AC-2 (1)" />
+      </references>
+    </cci_item>
+    <cci_item id="CCI-000016">
+      <status>draft</status>
+      <publishdate>2009-05-13</publishdate>
+      <contributor>DISA FSO</contributor>
+      <definition>Automatically remove or disable temporary accounts.</definition>
+      <type>technical</type>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 4" version="4" location="http://csrc.nist.gov/publications/PubsSPs.html" index="AC-2 (2)" />
+      </references>
+    </cci_item>
+    <cci_item id="CCI-000366">
+      <status>draft</status>
+      <publishdate>2009-09-18</publishdate>
+      <contributor>DISA FSO</contributor>
+      <definition>Implement the security configuration settings.</definition>
+      <type>policy</type>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" location="https://csrc.nist.gov/pubs/sp/800/53/r5/upd1/final" index="CM-6 b" />
+      </references>
+    </cci_item>
+  </cci_items>
+</cci_list>`
+
+func TestCCIIndex(t *testing.T) {
+	index, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	if index.Version != "2025-09-19" {
+		t.Errorf("index.Version = %s, expected 2025-09-19", index.Version)
+	}
+	if index.Len() != 3 {
+		t.Errorf("index.Len() = %d, expected 3", index.Len())
+	}
+
+	item, ok := index.Get("CCI-000015")
+	if !ok {
+		t.Fatal("index.Get(CCI-000015) not found")
+	}
+	if item.Type != "technical" || item.Contributor != "DISA FSO" || len(item.References.References) != 4 {
+		t.Errorf("index.Get(CCI-000015) = %+v", item)
+	}
+	if len(item.NISTReferences()) != 4 {
+		t.Errorf("NISTReferences() returned %d references, expected 4", len(item.NISTReferences()))
+	}
+
+	if controlID := index.ControlID("CCI-000016"); controlID != "ac-2.2" {
+		t.Errorf("index.ControlID(CCI-000016) = %s, expected ac-2.2", controlID)
+	}
+
+	byControl := index.ByControl("AC-2.1")
+	if len(byControl) != 1 || byControl[0].ID != "CCI-000015" {
+		t.Errorf("index.ByControl(AC-2.1) = %v, expected CCI-000015", byControl)
+	}
+
+	if statementID := index.StatementID("CCI-000366"); statementID != "cm-6_smt.b" {
+		t.Errorf("index.StatementID(CCI-000366) = %s, expected cm-6_smt.b", statementID)
+	}
+	if statementID := index.StatementID("CCI-000015"); statementID != "" {
+		t.Errorf("index.StatementID(CCI-000015) = %s, expected none", statementID)
+	}
+
+	rev5 := index.ByReference("NIST SP 800-53 Revision 5", "5")
+	if len(rev5) != 2 {
+		t.Errorf("index.ByReference(Rev 5) returned %d items, expected 2", len(rev5))
+	}
+
+	controls := index.Controls()
+	if len(controls) != 3 || controls[0] != "ac-2.1" || controls[2] != "cm-6" {
+		t.Errorf("index.Controls() = %v", controls)
+	}
+}
+
+func TestPreferenceForRevision(t *testing.T) {
+	preference, err := PreferenceForRevision("4", true)
+	if err != nil {
+		t.Fatalf("PreferenceForRevision(4, true) returned error: %v", err)
+	}
+	if len(preference) != 4 || preference[0].Title != "NIST SP 800-53 Revision 4" || preference[1].Title != "NIST SP 800-53 Revision 5" {
+		t.Errorf("PreferenceForRevision(4, true) = %v", preference)
+	}
+
+	if _, err := PreferenceForRevision("2", true); err == nil {
+		t.Error("PreferenceForRevision(2, true) returned no error")
+	}
+
+	index, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	strict, err := PreferenceForRevision("5", false)
+	if err != nil {
+		t.Fatalf("PreferenceForRevision(5, false) returned error: %v", err)
+	}
+	index.SetPreference(strict)
+
+	unmapped := index.Unmapped([]string{"CCI-000015", "CCI-000016", "CCI-000366"})
+	if len(unmapped) != 1 || unmapped[0] != "CCI-000016" {
+		t.Errorf("index.Unmapped() with strict Rev 5 = %v, expected [CCI-000016]", unmapped)
+	}
+	if controlID := index.ControlID("CCI-000015"); controlID != "ac-2.1" {
+		t.Errorf("index.ControlID(CCI-000015) = %s, expected ac-2.1", controlID)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	index, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	checklist := &cklb.Checklist{}
+	checklist.Data.STIGs = []cklb.STIG{{
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-1r1", Status: "not_a_finding", CCIs: []string{"CCI-000015"}},
+			{RuleID: "SV-2r1", Status: "not_a_finding", CCIs: []string{"CCI-000366"}},
+			{RuleID: "SV-3r1", Status: "open", CCIs: []string{"CCI-000366"}},
+		},
+	}}
+
+	coverage := index.Coverage(checklist)
+	if len(coverage) != 2 {
+		t.Fatalf("Coverage() returned %d controls, expected 2", len(coverage))
+	}
+
+	if coverage[0].ControlID != "ac-2.1" || !coverage[0].Met() {
+		t.Errorf("Coverage()[0] = %+v, expected ac-2.1 met", coverage[0])
+	}
+	if coverage[1].ControlID != "cm-6" || coverage[1].Met() || coverage[1].Count(CoverageOpen) != 1 {
+		t.Errorf("Coverage()[1] = %+v, expected cm-6 open", coverage[1])
+	}
+	if rules := coverage[1].CCIs[0].Rules; len(rules) != 2 {
+		t.Errorf("Coverage()[1] rules = %v, expected SV-2r1 and SV-3r1", rules)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	older, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	newXML := strings.NewReplacer(
+		`index="CM-6 b"`, `index="CM-7 a"`,
+		"Automatically remove or disable temporary accounts.", "Automatically remove temporary accounts.",
+		`<cci_item id="CCI-000015">`, `<cci_item id="CCI-000017">`,
+	).Replace(testIndexXML)
+	newer, err := ParseCCIIndexReader(strings.NewReader(newXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	diff := older.Diff(newer)
+	if len(diff.Added) != 1 || diff.Added[0] != "CCI-000017" {
+		t.Errorf("diff.Added = %v, expected [CCI-000017]", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "CCI-000015" {
+		t.Errorf("diff.Removed = %v, expected [CCI-000015]", diff.Removed)
+	}
+	if len(diff.DefinitionChanges) != 1 || diff.DefinitionChanges[0].ID != "CCI-000016" {
+		t.Errorf("diff.DefinitionChanges = %v, expected CCI-000016", diff.DefinitionChanges)
+	}
+	if len(diff.MappingChanges) != 1 || diff.MappingChanges[0].NewControl != "cm-7" {
+		t.Errorf("diff.MappingChanges = %v, expected CCI-000366 to cm-7", diff.MappingChanges)
+	}
+
+	affected := diff.Affected([]string{"CCI-000366", "CCI-000017", "CCI-000366"})
+	if len(affected) != 1 || affected[0] != "CCI-000366" {
+		t.Errorf("diff.Affected() = %v, expected [CCI-000366]", affected)
+	}
+
+	if !older.Diff(older).Empty() {
+		t.Error("Diff() of an index with itself is not empty")
+	}
+}
+
+func TestCompiledIndexCache(t *testing.T) {
+	CacheDir = t.TempDir()
+	defer func() { CacheDir = "" }()
+
+	listPath := filepath.Join(t.TempDir(), "cci_list.xml")
+	if err := os.WriteFile(listPath, []byte(testIndexXML), 0644); err != nil {
+		t.Fatalf("failed to write CCI list: %v", err)
+	}
+
+	parsed, err := LoadCCIIndex(listPath)
+	if err != nil {
+		t.Fatalf("LoadCCIIndex() returned error: %v", err)
+	}
+	cachePath := cachedIndexPath(HashCCIList([]byte(testIndexXML)))
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("LoadCCIIndex() did not cache a compiled index: %v", err)
+	}
+
+	cached, err := LoadCCIIndex(listPath)
+	if err != nil {
+		t.Fatalf("LoadCCIIndex() from cache returned error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed.ControlMap(), cached.ControlMap()) || cached.Version != parsed.Version || cached.Len() != parsed.Len() {
+		t.Errorf("cached index differs from parsed index")
+	}
+	if cached.StatementID("CCI-000366") != "cm-6_smt.b" {
+		t.Errorf("cached index StatementID(CCI-000366) = %s, expected cm-6_smt.b", cached.StatementID("CCI-000366"))
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteCompiledIndex(&buffer, parsed, "other"); err != nil {
+		t.Fatalf("WriteCompiledIndex() returned error: %v", err)
+	}
+	if _, err := readCompiledIndex(&buffer, HashCCIList([]byte(testIndexXML))); err == nil {
+		t.Error("readCompiledIndex() accepted an index compiled from another list")
+	}
+}
+
+func TestImportCCIList(t *testing.T) {
+	DataDir = t.TempDir()
+	CacheDir = t.TempDir()
+	defer func() { DataDir, CacheDir = "", "" }()
+
+	if source := ResolveCCISource(""); source.Kind != SourceEmbedded {
+		t.Errorf("ResolveCCISource() before import = %s, expected embedded", source.Kind)
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "U_CCI_List.zip")
+	file, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	archive := zip.NewWriter(file)
+	writer, err := archive.Create("U_CCI_List.xml")
+	if err != nil {
+		t.Fatalf("failed to add zip entry: %v", err)
+	}
+	if _, err := writer.Write([]byte(testIndexXML)); err != nil {
+		t.Fatalf("failed to write zip entry: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+
+	index, path, err := ImportCCIList(zipPath)
+	if err != nil {
+		t.Fatalf("ImportCCIList() returned error: %v", err)
+	}
+	if index.Version != "2025-09-19" || path != ImportedListPath() {
+		t.Errorf("ImportCCIList() = %s, %s", index.Version, path)
+	}
+
+	source := ResolveCCISource("")
+	if source.Kind != SourceImported || source.Path != ImportedListPath() {
+		t.Errorf("ResolveCCISource() after import = %+v, expected the imported list", source)
+	}
+	if source := ResolveCCISource("custom.xml"); source.Kind != SourceCustom {
+		t.Errorf("ResolveCCISource(custom.xml) = %s, expected custom", source.Kind)
+	}
+
+	loaded, err := LoadCCIIndex("")
+	if err != nil {
+		t.Fatalf("LoadCCIIndex() returned error: %v", err)
+	}
+	if loaded.Len() != 3 {
+		t.Errorf("LoadCCIIndex() of the imported list returned %d CCIs, expected 3", loaded.Len())
+	}
+}
+
+func TestObjectiveID(t *testing.T) {
+	index, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	if objectiveID := index.ObjectiveID("CCI-000366"); objectiveID != "cm-6_obj.b" {
+		t.Errorf("index.ObjectiveID(CCI-000366) = %s, expected cm-6_obj.b", objectiveID)
+	}
+	if objectiveID := index.ObjectiveID("CCI-000015"); objectiveID != "ac-2.1_obj" {
+		t.Errorf("index.ObjectiveID(CCI-000015) = %s, expected ac-2.1_obj", objectiveID)
+	}
+
+	testCases := []struct {
+		index    string
+		expected int
+	}{
+		{"AC-2.1 (ii)", 2},
+		{"CM-6.1 (iv)", 4},
+		{"AC-2 (1).1", 0},
+	}
+	for _, tc := range testCases {
+		item := CCIItem{References: References{References: []Reference{{Title: AssessmentReferenceTitle, Index: tc.index}}}}
+		if determination := item.AssessmentDetermination(); determination != tc.expected {
+			t.Errorf("AssessmentDetermination(%s) = %d, expected %d", tc.index, determination, tc.expected)
+		}
+	}
+}
+
+func TestCheckStatuses(t *testing.T) {
+	listXML := strings.NewReplacer(
+		"<status>draft</status>\n      <publishdate>2009-05-13</publishdate>\n      <contributor>DISA FSO</contributor>\n      <definition>Automatically remove or disable temporary accounts.</definition>",
+		"<status>deprecated</status>\n      <publishdate>2009-05-13</publishdate>\n      <contributor>DISA FSO</contributor>\n      <definition>Deprecated, use CCI-000366.</definition>",
+	).Replace(testIndexXML)
+	index, err := ParseCCIIndexReader(strings.NewReader(listXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	checklist := &cklb.Checklist{}
+	checklist.Data.STIGs = []cklb.STIG{{
+		STIGID: "Test_STIG",
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-1r1", CCIs: []string{"CCI-000016"}},
+			{RuleID: "SV-2r1", CCIs: []string{"CCI-000366", "CCI-999999"}},
+		},
+	}}
+
+	findings := index.CheckStatuses(checklist)
+	if len(findings) != 2 {
+		t.Fatalf("CheckStatuses() returned %d findings, expected 2", len(findings))
+	}
+	if !findings[0].Deprecated() || findings[0].RuleID != "SV-1r1" || len(findings[0].Replacements) != 1 || findings[0].Replacements[0] != "CCI-000366" {
+		t.Errorf("CheckStatuses()[0] = %+v, expected deprecated CCI-000016 replaced by CCI-000366", findings[0])
+	}
+	if findings[1].Status != StatusDraft || findings[1].CCI != "CCI-000366" {
+		t.Errorf("CheckStatuses()[1] = %+v, expected draft CCI-000366", findings[1])
+	}
+
+	expected := "rule SV-1r1 cites deprecated CCI-000016, replaced by CCI-000366 -> cm-6"
+	if description := index.DescribeFinding(findings[0]); description != expected {
+		t.Errorf("DescribeFinding() = %q, expected %q", description, expected)
+	}
+
+	var out strings.Builder
+	if err := index.ReportStatuses(&out, checklist, false, false); err != nil {
+		t.Errorf("ReportStatuses() returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Note: rule SV-2r1 cites draft CCI-000366\n") {
+		t.Errorf("ReportStatuses() output does not name the rule citing a draft CCI:\n%s", out.String())
+	}
+	out.Reset()
+	if err := index.ReportStatuses(&out, checklist, true, true); err == nil {
+		t.Error("ReportStatuses() with failOnDeprecated returned no error")
+	}
+	if strings.Contains(out.String(), "SV-2r1") || !strings.Contains(out.String(), "Note: 1 rules cite CCIs") {
+		t.Errorf("ReportStatuses() with quietDraft output:\n%s", out.String())
+	}
+}
+
+func TestSetOverrides(t *testing.T) {
+	index, err := ParseCCIIndexReader(strings.NewReader(testIndexXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+
+	overrides, err := ParseOverrides(strings.NewReader("cci,control\nccI-000016,AC-2 (3)\nCCI-000366,cm-7_smt.a\nCCI-999999,ac-1\n"))
+	if err != nil {
+		t.Fatalf("ParseOverrides() returned error: %v", err)
+	}
+
+	unknown := index.SetOverrides(overrides)
+	if len(unknown) != 1 || unknown[0] != "CCI-999999" {
+		t.Errorf("SetOverrides() unknown = %v, expected [CCI-999999]", unknown)
+	}
+	if controlID := index.ControlID("CCI-000016"); controlID != "ac-2.3" {
+		t.Errorf("index.ControlID(CCI-000016) = %s, expected ac-2.3", controlID)
+	}
+	if controlID, statementID := index.ControlID("CCI-000366"), index.StatementID("CCI-000366"); controlID != "cm-7" || statementID != "cm-7_smt.a" {
+		t.Errorf("CCI-000366 maps to %s, %s, expected cm-7, cm-7_smt.a", controlID, statementID)
+	}
+
+	// Overrides survive a change of revision
+	strict, err := PreferenceForRevision("4", false)
+	if err != nil {
+		t.Fatalf("PreferenceForRevision() returned error: %v", err)
+	}
+	index.SetPreference(strict)
+	if controlID := index.ControlID("CCI-000366"); controlID != "cm-7" {
+		t.Errorf("index.ControlID(CCI-000366) after SetPreference = %s, expected cm-7", controlID)
+	}
+
+	for _, control := range []string{"cm-six", "mystuff", "ac-2.4.1", "ac-2_smt.a b"} {
+		_, err := ParseOverrides(strings.NewReader("cci,control\nCCI-000016,ac-2\nCCI-000366," + control + "\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 3") {
+			t.Errorf("ParseOverrides() of control %q returned %v, expected an error on line 3", control, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cciparsing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+//go:generate go run gen_index.go
+
+// compiledIndexFormat is bumped whenever the layout of compiledIndex changes,
+// which invalidates every cached index
+const compiledIndexFormat = 1
+
+// CacheDir is the directory compiled indexes of custom CCI lists are cached in.
+// If empty, an oscalctl directory below the user's cache directory is used.
+var CacheDir string
+
+// compiledIndex is the precompiled form of a CCI list
+type compiledIndex struct {
+	Format      int
+	SourceHash  string
+	Version     string
+	PublishDate string
+	Items       []CCIItem
+}
+
+// HashCCIList returns the hash a compiled index of a CCI XML document is keyed by
+func HashCCIList(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteCompiledIndex writes the index in its precompiled form, keyed by the hash
+// of the CCI XML document it was parsed from
+func WriteCompiledIndex(writer io.Writer, index *CCIIndex, sourceHash string) error {
+	return gob.NewEncoder(writer).Encode(compiledIndex{
+		Format:      compiledIndexFormat,
+		SourceHash:  sourceHash,
+		Version:     index.Version,
+		PublishDate: index.PublishDate,
+		Items:       index.items,
+	})
+}
+
+// readCompiledIndex reads a precompiled index, which must have been compiled
+// from the CCI XML document with the given hash
+func readCompiledIndex(reader io.Reader, sourceHash string) (*CCIIndex, error) {
+	var compiled compiledIndex
+	if err := gob.NewDecoder(reader).Decode(&compiled); err != nil {
+		return nil, err
+	}
+	if compiled.Format != compiledIndexFormat {
+		return nil, fmt.Errorf("compiled CCI index has format %d, expected %d", compiled.Format, compiledIndexFormat)
+	}
+	if compiled.SourceHash != sourceHash {
+		return nil, fmt.Errorf("compiled CCI index is out of date")
+	}
+
+	return NewCCIIndex(&CCIDocument{
+		Metadata: CCIMetadata{Version: compiled.Version, PublishDate: compiled.PublishDate},
+		CCIItems: compiled.Items,
+	}), nil
+}
+
+// loadCachedCCIIndex parses a CCI XML document, using the compiled index cached for
+// its hash if there is one and caching a compiled index otherwise
+func loadCachedCCIIndex(data []byte) (*CCIIndex, error) {
+	sourceHash := HashCCIList(data)
+	cachePath := cachedIndexPath(sourceHash)
+
+	if cachePath != "" {
+		if cached, err := os.ReadFile(cachePath); err == nil {
+			if index, err := readCompiledIndex(bytes.NewReader(cached), sourceHash); err == nil {
+				return index, nil
+			}
+		}
+	}
+
+	index, err := ParseCCIIndexReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Caching is best effort, a read-only cache only costs the next run its speed
+	if cachePath != "" {
+		if err := writeCachedIndex(cachePath, index, sourceHash); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache CCI index: %v\n", err)
+		}
+	}
+
+	return index, nil
+}
+
+// cachedIndexPath returns the cache file of a CCI list hash, or "" if there is no cache directory
+func cachedIndexPath(sourceHash string) string {
+	dir := CacheDir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(userCacheDir, "oscalctl", "cci")
+	}
+	return filepath.Join(dir, sourceHash+".gob")
+}
+
+func writeCachedIndex(path string, index *CCIIndex, sourceHash string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteCompiledIndex(&buffer, index, sourceHash); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent runs never read a partial index
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buffer.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
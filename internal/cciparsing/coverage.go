@@ -0,0 +1,128 @@
+package cciparsing
+
+import (
+	"sort"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// CCI coverage states
+const (
+	CoverageSatisfied     = "satisfied"
+	CoverageNotApplicable = "not_applicable"
+	CoverageNotReviewed   = "not_reviewed"
+	CoverageOpen          = "open"
+	CoverageNoRule        = "no_rule"
+)
+
+// CCICoverage holds the assessment state of a CCI across the rules citing it
+type CCICoverage struct {
+	ID    string
+	State string
+	// Rules lists the rule ids citing the CCI
+	Rules []string
+}
+
+// ControlCoverage lists the CCIs of a control by assessment state
+type ControlCoverage struct {
+	ControlID string
+	CCIs      []CCICoverage
+}
+
+// Count returns the number of CCIs of the control in the given state
+func (c ControlCoverage) Count(state string) int {
+	count := 0
+	for _, cci := range c.CCIs {
+		if cci.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+// Met reports whether every CCI of the control is satisfied or not applicable
+func (c ControlCoverage) Met() bool {
+	for _, cci := range c.CCIs {
+		if cci.State != CoverageSatisfied && cci.State != CoverageNotApplicable {
+			return false
+		}
+	}
+	return true
+}
+
+// Coverage reports, for every control cited by the checklist, the state of each of its
+// CCIs. A CCI is open if any rule citing it is open, not reviewed if any is not yet
+// reviewed, satisfied if any is not_a_finding and not applicable otherwise. CCIs of the
+// control that no rule cites are reported as no_rule.
+func (x *CCIIndex) Coverage(checklist *cklb.Checklist) []ControlCoverage {
+	states := make(map[string]*CCICoverage)
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			state := ruleCoverageState(rule.Status)
+			for _, cci := range rule.CCIs {
+				coverage, ok := states[cci]
+				if !ok {
+					coverage = &CCICoverage{ID: cci, State: state}
+					states[cci] = coverage
+				} else if coverageRank(state) > coverageRank(coverage.State) {
+					coverage.State = state
+				}
+				coverage.Rules = append(coverage.Rules, rule.RuleID)
+			}
+		}
+	}
+
+	controls := make(map[string]bool)
+	for cci := range states {
+		if controlID := x.ControlID(cci); controlID != "" {
+			controls[controlID] = true
+		}
+	}
+
+	var coverage []ControlCoverage
+	for controlID := range controls {
+		control := ControlCoverage{ControlID: controlID}
+		for _, item := range x.ByControl(controlID) {
+			if state, ok := states[item.ID]; ok {
+				control.CCIs = append(control.CCIs, *state)
+			} else {
+				control.CCIs = append(control.CCIs, CCICoverage{ID: item.ID, State: CoverageNoRule})
+			}
+		}
+		coverage = append(coverage, control)
+	}
+
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].ControlID < coverage[j].ControlID
+	})
+	return coverage
+}
+
+// ruleCoverageState maps a CKLB rule status to a CCI coverage state
+func ruleCoverageState(status string) string {
+	switch status {
+	case "not_a_finding":
+		return CoverageSatisfied
+	case "not_applicable":
+		return CoverageNotApplicable
+	case "open":
+		return CoverageOpen
+	default:
+		return CoverageNotReviewed
+	}
+}
+
+// coverageRank orders coverage states so that the worst state of a CCI wins
+func coverageRank(state string) int {
+	switch state {
+	case CoverageNotApplicable:
+		return 0
+	case CoverageSatisfied:
+		return 1
+	case CoverageNotReviewed:
+		return 2
+	case CoverageOpen:
+		return 3
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package cciparsing
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefinitionChange records a CCI whose definition or status changed between two lists
+type DefinitionChange struct {
+	ID        string
+	OldStatus string
+	NewStatus string
+	Old       string
+	New       string
+}
+
+// MappingChange records a CCI that maps to a different control between two lists
+type MappingChange struct {
+	ID         string
+	OldControl string
+	NewControl string
+}
+
+// CCIDiff holds the differences between two versions of the CCI list
+type CCIDiff struct {
+	OldVersion        string
+	NewVersion        string
+	Added             []string
+	Removed           []string
+	DefinitionChanges []DefinitionChange
+	MappingChanges    []MappingChange
+}
+
+// Diff compares the index with a newer version of the CCI list. Control mappings are
+// compared under the reference preference of each index.
+func (x *CCIIndex) Diff(newer *CCIIndex) CCIDiff {
+	diff := CCIDiff{OldVersion: x.Version, NewVersion: newer.Version}
+
+	for _, item := range x.items {
+		newItem, ok := newer.Get(item.ID)
+		if !ok {
+			diff.Removed = append(diff.Removed, item.ID)
+			continue
+		}
+
+		oldDefinition := strings.Join(strings.Fields(item.Definition), " ")
+		newDefinition := strings.Join(strings.Fields(newItem.Definition), " ")
+		if oldDefinition != newDefinition || item.Status != newItem.Status {
+			diff.DefinitionChanges = append(diff.DefinitionChanges, DefinitionChange{
+				ID:        item.ID,
+				OldStatus: item.Status,
+				NewStatus: newItem.Status,
+				Old:       oldDefinition,
+				New:       newDefinition,
+			})
+		}
+
+		if oldControl, newControl := x.ControlID(item.ID), newer.ControlID(item.ID); oldControl != newControl {
+			diff.MappingChanges = append(diff.MappingChanges, MappingChange{
+				ID:         item.ID,
+				OldControl: oldControl,
+				NewControl: newControl,
+			})
+		}
+	}
+
+	for _, item := range newer.items {
+		if _, ok := x.Get(item.ID); !ok {
+			diff.Added = append(diff.Added, item.ID)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// Empty reports whether the two lists have no differences
+func (d CCIDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.DefinitionChanges) == 0 && len(d.MappingChanges) == 0
+}
+
+// Affected returns the given CCIs that were removed or whose definition, status or
+// control mapping changed, without duplicates
+func (d CCIDiff) Affected(ccis []string) []string {
+	changed := make(map[string]bool)
+	for _, id := range d.Removed {
+		changed[id] = true
+	}
+	for _, change := range d.DefinitionChanges {
+		changed[change.ID] = true
+	}
+	for _, change := range d.MappingChanges {
+		changed[change.ID] = true
+	}
+
+	var affected []string
+	seen := make(map[string]bool)
+	for _, id := range ccis {
+		if changed[id] && !seen[id] {
+			seen[id] = true
+			affected = append(affected, id)
+		}
+	}
+	sort.Strings(affected)
+	return affected
+}
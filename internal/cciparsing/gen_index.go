@@ -0,0 +1,43 @@
+//go:build ignore
+
+// gen_index precompiles the embedded CCI list into assets/cci_index.gob.
+// Run it with go generate after replacing assets/cci_list.xml.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+)
+
+func main() {
+	if err := generate("assets/cci_list.xml", "assets/cci_index.gob"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating CCI index: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(listPath, indexPath string) error {
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return err
+	}
+
+	index, err := cciparsing.ParseCCIIndexReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := cciparsing.WriteCompiledIndex(&buffer, index, cciparsing.HashCCIList(data)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(indexPath, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Compiled %d CCIs from CCI list %s into %s\n", index.Len(), index.Version, indexPath)
+	return nil
+}
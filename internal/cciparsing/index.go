@@ -0,0 +1,260 @@
+package cciparsing
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CCIIndex keeps the full CCI list and offers lookups by CCI, by control and by reference
+type CCIIndex struct {
+	Version     string
+	PublishDate string
+
+	items      []CCIItem
+	byID       map[string]int
+	preference []ReferencePreference
+	overrides  map[string]string
+	controls   map[string]string
+	statements map[string]string
+	byControl  map[string][]int
+}
+
+// NewCCIIndex builds an index from a parsed CCI document, mapping CCIs to
+// controls with DefaultPreference
+func NewCCIIndex(document *CCIDocument) *CCIIndex {
+	index := &CCIIndex{
+		Version:     document.Metadata.Version,
+		PublishDate: document.Metadata.PublishDate,
+		items:       document.CCIItems,
+		byID:        make(map[string]int),
+	}
+
+	for i, item := range index.items {
+		index.byID[item.ID] = i
+	}
+	index.SetPreference(DefaultPreference)
+
+	return index
+}
+
+// SetPreference remaps every CCI to the control named by its most preferred reference
+func (x *CCIIndex) SetPreference(preference []ReferencePreference) {
+	x.preference = preference
+	x.remap()
+}
+
+// SetOverrides maps CCIs to the given control or statement ids, such as ac-2.4 or
+// ac-2_smt.a, in place of their references. It returns the CCIs that are not in
+// the list, whose overrides are ignored.
+func (x *CCIIndex) SetOverrides(overrides map[string]string) []string {
+	x.overrides = make(map[string]string)
+	var unknown []string
+	for id, target := range overrides {
+		if _, ok := x.byID[id]; !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		x.overrides[id] = target
+	}
+	sort.Strings(unknown)
+	x.remap()
+	return unknown
+}
+
+// remap maps every CCI to its overridden control, or to the control named by its
+// most preferred reference
+func (x *CCIIndex) remap() {
+	x.controls = make(map[string]string)
+	x.statements = make(map[string]string)
+	x.byControl = make(map[string][]int)
+
+	for i, item := range x.items {
+		var controlID, statementID string
+		if target, ok := x.overrides[item.ID]; ok {
+			controlID, statementID = splitOverride(target)
+		} else {
+			reference := getPreferredReference(item.References.References, x.preference)
+			if reference == nil {
+				continue
+			}
+			controlID = normalizeControlID(reference.Index)
+			statementID = statementIDForReference(reference)
+		}
+
+		// Only include control IDs that match the OSCAL token pattern
+		if controlID != "" && IsValidOSCALToken(controlID) {
+			x.controls[item.ID] = controlID
+			x.byControl[controlID] = append(x.byControl[controlID], i)
+
+			if strings.HasPrefix(statementID, controlID+"_smt.") && IsValidOSCALToken(statementID) {
+				x.statements[item.ID] = statementID
+			}
+		}
+	}
+}
+
+// splitOverride splits an override target into its control and statement ids. Targets
+// may be OSCAL ids (ac-2.4, ac-2_smt.a) or NIST notation (AC-2 (4), AC-2 a).
+func splitOverride(target string) (string, string) {
+	target = strings.TrimSpace(target)
+	if i := strings.Index(target, "_smt"); i > 0 {
+		return strings.ToLower(target[:i]), strings.ToLower(target)
+	}
+	if lower := strings.ToLower(target); IsValidOSCALToken(lower) {
+		return lower, ""
+	}
+	upper := strings.ToUpper(target)
+	return normalizeControlID(upper), normalizeStatementID(upper)
+}
+
+// Preference returns the reference order used to map CCIs to controls
+func (x *CCIIndex) Preference() []ReferencePreference {
+	return x.preference
+}
+
+// ParseCCIIndexReader parses a CCI XML document from a reader into an index
+func ParseCCIIndexReader(reader io.Reader) (*CCIIndex, error) {
+	var document CCIDocument
+	decoder := xml.NewDecoder(reader)
+
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return NewCCIIndex(&document), nil
+}
+
+// GetEmbeddedCCIIndex loads the embedded CCI XML document into an index, from the
+// index precompiled by go generate if it is up to date
+func GetEmbeddedCCIIndex() (*CCIIndex, error) {
+	data, err := embeddedFS.ReadFile("assets/cci_list.xml")
+	if err != nil {
+		return nil, fmt.Errorf("no CCI list is embedded in this build, import one with 'oscalctl cci import' or pass --cci-map")
+	}
+
+	if compiled, err := embeddedFS.ReadFile("assets/cci_index.gob"); err == nil {
+		if index, err := readCompiledIndex(bytes.NewReader(compiled), HashCCIList(data)); err == nil {
+			return index, nil
+		}
+	}
+
+	return ParseCCIIndexReader(bytes.NewReader(data))
+}
+
+// LoadCCIIndex parses a CCI XML document from a file path into an index, using a
+// compiled index cached in CacheDir when the file was loaded before.
+// If filePath is empty, uses the imported CCI list, or the embedded one if none was imported
+func LoadCCIIndex(filePath string) (*CCIIndex, error) {
+	return ResolveCCISource(filePath).Load()
+}
+
+// Len returns the number of CCIs in the index
+func (x *CCIIndex) Len() int {
+	return len(x.items)
+}
+
+// Items returns every CCI in document order
+func (x *CCIIndex) Items() []CCIItem {
+	return x.items
+}
+
+// Get returns the CCI with the given id
+func (x *CCIIndex) Get(id string) (CCIItem, bool) {
+	i, ok := x.byID[id]
+	if !ok {
+		return CCIItem{}, false
+	}
+	return x.items[i], true
+}
+
+// ControlID returns the preferred OSCAL control id of a CCI, or "" if it has none
+func (x *CCIIndex) ControlID(id string) string {
+	return x.controls[id]
+}
+
+// Unmapped returns the given CCIs that have no control under the current preference,
+// without duplicates
+func (x *CCIIndex) Unmapped(ids []string) []string {
+	var unmapped []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if x.controls[id] == "" && !seen[id] {
+			seen[id] = true
+			unmapped = append(unmapped, id)
+		}
+	}
+	return unmapped
+}
+
+// StatementID returns the OSCAL statement id of a CCI, such as ac-2_smt.a.1,
+// or "" if its reference names the control as a whole
+func (x *CCIIndex) StatementID(id string) string {
+	return x.statements[id]
+}
+
+// ByControl returns the CCIs whose preferred control id is controlID
+func (x *CCIIndex) ByControl(controlID string) []CCIItem {
+	var items []CCIItem
+	for _, i := range x.byControl[strings.ToLower(controlID)] {
+		items = append(items, x.items[i])
+	}
+	return items
+}
+
+// Controls returns every control id that at least one CCI maps to, sorted
+func (x *CCIIndex) Controls() []string {
+	controls := make([]string, 0, len(x.byControl))
+	for controlID := range x.byControl {
+		controls = append(controls, controlID)
+	}
+	sort.Strings(controls)
+	return controls
+}
+
+// ByReference returns the CCIs that carry a reference with the given title.
+// If version is not empty, the reference version must match as well.
+func (x *CCIIndex) ByReference(title, version string) []CCIItem {
+	var items []CCIItem
+	for _, item := range x.items {
+		if len(item.ReferencesByTitle(title, version)) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ControlMap returns a map of CCI ids to their preferred control ids
+func (x *CCIIndex) ControlMap() map[string]string {
+	result := make(map[string]string, len(x.controls))
+	for id, controlID := range x.controls {
+		result[id] = controlID
+	}
+	return result
+}
+
+// ReferencesByTitle returns the references of a CCI with the given title and,
+// if not empty, version
+func (item CCIItem) ReferencesByTitle(title, version string) []Reference {
+	var references []Reference
+	for _, reference := range item.References.References {
+		if reference.Title == title && (version == "" || reference.Version == version) {
+			references = append(references, reference)
+		}
+	}
+	return references
+}
+
+// NISTReferences returns the NIST SP 800-53 and 800-53A references of a CCI
+func (item CCIItem) NISTReferences() []Reference {
+	var references []Reference
+	for _, reference := range item.References.References {
+		if strings.HasPrefix(reference.Title, "NIST SP 800-53") {
+			references = append(references, reference)
+		}
+	}
+	return references
+}
@@ -0,0 +1,55 @@
+package cciparsing
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AssessmentReferenceTitle is the title of CCI references to NIST SP 800-53A assessment procedures
+const AssessmentReferenceTitle = "NIST SP 800-53A"
+
+// determinationPattern matches the determination statement of an 800-53A index like 'AC-2.1 (ii)'
+var determinationPattern = regexp.MustCompile(`\(([ivxlcIVXLC]+)\)\s*$`)
+
+// romanValues holds the values of the roman numerals used for determination statements
+var romanValues = map[rune]int{'i': 1, 'v': 5, 'x': 10, 'l': 50, 'c': 100}
+
+// ObjectiveID returns the OSCAL assessment objective id of a CCI, such as ac-2_obj.a
+// for a CCI mapped to ac-2_smt.a, or ac-2.4_obj for one mapped to ac-2.4 as a whole.
+// It returns "" if the CCI has no control.
+func (x *CCIIndex) ObjectiveID(id string) string {
+	controlID := x.controls[id]
+	if controlID == "" {
+		return ""
+	}
+	if statementID := x.statements[id]; statementID != "" {
+		return strings.Replace(statementID, "_smt", "_obj", 1)
+	}
+	return controlID + "_obj"
+}
+
+// AssessmentDetermination returns the determination statement number of the CCI's
+// 800-53A reference, for example 2 for 'AC-2.1 (ii)', or 0 if it names none
+func (item CCIItem) AssessmentDetermination() int {
+	for _, reference := range item.ReferencesByTitle(AssessmentReferenceTitle, "") {
+		if matches := determinationPattern.FindStringSubmatch(reference.Index); matches != nil {
+			return romanToInt(strings.ToLower(matches[1]))
+		}
+	}
+	return 0
+}
+
+// romanToInt converts a lower case roman numeral to an integer
+func romanToInt(numeral string) int {
+	total := 0
+	runes := []rune(numeral)
+	for i, r := range runes {
+		value := romanValues[r]
+		if i+1 < len(runes) && value < romanValues[runes[i+1]] {
+			total -= value
+		} else {
+			total += value
+		}
+	}
+	return total
+}
@@ -0,0 +1,91 @@
+package cciparsing
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// overrideControlPattern matches the 800-53 control ids an override may map a CCI
+// to, such as ac-2 or ac-2.4
+var overrideControlPattern = regexp.MustCompile(`^[a-z]{2}-\d+(\.\d+)?$`)
+
+// LoadOverrides reads CCI overrides from a CSV file with a header row and the
+// columns cci and control, for example "CCI-000366,cm-6_smt.b"
+func LoadOverrides(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	return ParseOverrides(file)
+}
+
+// ParseOverrides reads CCI overrides in CSV format from a reader. Controls that do
+// not name an 800-53 control or statement are rejected with their line number.
+func ParseOverrides(reader io.Reader) (map[string]string, error) {
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CCI overrides are empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cciColumn, controlColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "cci":
+			cciColumn = i
+		case "control", "control-id":
+			controlColumn = i
+		}
+	}
+	if cciColumn < 0 || controlColumn < 0 {
+		return nil, fmt.Errorf("CCI overrides need cci and control columns")
+	}
+
+	overrides := make(map[string]string)
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cciColumn >= len(record) || controlColumn >= len(record) {
+			continue
+		}
+		cci := strings.ToUpper(strings.TrimSpace(record[cciColumn]))
+		control := strings.TrimSpace(record[controlColumn])
+		if cci == "" || control == "" {
+			continue
+		}
+		if err := ValidateOverride(control); err != nil {
+			line, _ := csvReader.FieldPos(controlColumn)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		overrides[cci] = control
+	}
+	return overrides, nil
+}
+
+// ValidateOverride checks that an override target names an 800-53 control or
+// statement, in OSCAL or NIST notation
+func ValidateOverride(target string) error {
+	controlID, statementID := splitOverride(target)
+	if !overrideControlPattern.MatchString(controlID) || (statementID != "" && !IsValidOSCALToken(statementID)) {
+		return fmt.Errorf("%q is not an 800-53 control or statement id, such as ac-2.4, ac-2_smt.a or AC-2 (4)", target)
+	}
+	return nil
+}
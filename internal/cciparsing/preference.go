@@ -0,0 +1,66 @@
+package cciparsing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReferencePreference selects CCI references by title and, if not empty, version
+type ReferencePreference struct {
+	Title   string
+	Version string
+}
+
+// Matches reports whether a reference has the preferred title and version
+func (p ReferencePreference) Matches(reference Reference) bool {
+	return reference.Title == p.Title && (p.Version == "" || reference.Version == p.Version)
+}
+
+// String returns the reference title, with the version if one is required
+func (p ReferencePreference) String() string {
+	if p.Version == "" {
+		return p.Title
+	}
+	return fmt.Sprintf("%s (version %s)", p.Title, p.Version)
+}
+
+// DefaultPreference is the reference order used when no revision is selected
+var DefaultPreference = []ReferencePreference{
+	{Title: "NIST SP 800-53 Revision 5"},
+	{Title: "NIST SP 800-53 Revision 4"},
+	{Title: "NIST SP 800-53"},
+	{Title: "NIST SP 800-53A"},
+}
+
+// revisionReferences maps NIST SP 800-53 revisions to their CCI reference
+var revisionReferences = map[string]ReferencePreference{
+	"5": {Title: "NIST SP 800-53 Revision 5"},
+	"4": {Title: "NIST SP 800-53 Revision 4"},
+}
+
+// PreferenceForRevision returns the reference order for a NIST SP 800-53 revision.
+// The revision's references come first; unless fallback is false, the remaining
+// references of DefaultPreference follow. An empty revision selects revision 5.
+func PreferenceForRevision(revision string, fallback bool) ([]ReferencePreference, error) {
+	revision = strings.TrimSpace(revision)
+	if revision == "" {
+		revision = "5"
+	}
+
+	selected, ok := revisionReferences[revision]
+	if !ok {
+		return nil, fmt.Errorf("unsupported NIST SP 800-53 revision %q, expected 4 or 5", revision)
+	}
+
+	preference := []ReferencePreference{selected}
+	if !fallback {
+		return preference, nil
+	}
+
+	for _, pref := range DefaultPreference {
+		if pref.Title != selected.Title {
+			preference = append(preference, pref)
+		}
+	}
+	return preference, nil
+}
@@ -0,0 +1,175 @@
+package cciparsing
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CCI list sources, in the order they are resolved
+const (
+	SourceCustom   = "custom"
+	SourceImported = "imported"
+	SourceEmbedded = "embedded"
+)
+
+// DataDir is the directory imported CCI lists are stored in.
+// If empty, the .oscalctl directory in the user's home directory is used.
+var DataDir string
+
+// CCISource identifies the CCI list in use
+type CCISource struct {
+	Kind string
+	// Path is the file of a custom or imported list
+	Path string
+}
+
+// ResolveCCISource returns the CCI list to use: the given path if not empty,
+// otherwise the imported list if there is one, otherwise the embedded list
+func ResolveCCISource(path string) CCISource {
+	if path != "" {
+		return CCISource{Kind: SourceCustom, Path: path}
+	}
+	if imported := ImportedListPath(); imported != "" {
+		if _, err := os.Stat(imported); err == nil {
+			return CCISource{Kind: SourceImported, Path: imported}
+		}
+	}
+	return CCISource{Kind: SourceEmbedded}
+}
+
+// Load loads the CCI list of the source into an index
+func (s CCISource) Load() (*CCIIndex, error) {
+	if s.Kind == SourceEmbedded {
+		return GetEmbeddedCCIIndex()
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadCachedCCIIndex(data)
+}
+
+// String describes the source for messages
+func (s CCISource) String() string {
+	if s.Path == "" {
+		return s.Kind + " CCI list"
+	}
+	return fmt.Sprintf("%s CCI list %s", s.Kind, s.Path)
+}
+
+// ImportedListPath returns the file the current imported CCI list is stored in,
+// or "" if there is no data directory
+func ImportedListPath() string {
+	dir := importDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "cci_list.xml")
+}
+
+func importDir() string {
+	dir := DataDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".oscalctl")
+	}
+	return filepath.Join(dir, "cci")
+}
+
+// cciListEntryPattern matches the CCI list in DISA's distribution zip, e.g. U_CCI_List.xml
+var cciListEntryPattern = regexp.MustCompile(`(?i)cci_list[^/]*\.xml$`)
+
+// ImportCCIList stores a CCI list, given as DISA's distribution zip or as the XML
+// document itself, as the imported list. A copy named after the list version is
+// kept next to it so earlier imports remain available, for example to diff.
+func ImportCCIList(path string) (*CCIIndex, string, error) {
+	data, err := readCCIList(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	index, err := ParseCCIIndexReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse CCI list: %w", err)
+	}
+	if index.Len() == 0 {
+		return nil, "", fmt.Errorf("%s contains no CCIs", path)
+	}
+
+	dir := importDir()
+	if dir == "" {
+		return nil, "", fmt.Errorf("cannot determine the oscalctl data directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, "", err
+	}
+
+	if index.Version != "" {
+		versioned := filepath.Join(dir, fmt.Sprintf("U_CCI_List-%s.xml", safeFileName(index.Version)))
+		if err := os.WriteFile(versioned, data, 0644); err != nil {
+			return nil, "", err
+		}
+	}
+
+	imported := ImportedListPath()
+	if err := os.WriteFile(imported, data, 0644); err != nil {
+		return nil, "", err
+	}
+
+	return index, imported, nil
+}
+
+// readCCIList reads a CCI XML document, from DISA's zip if path is a zip file
+func readCCIList(path string) ([]byte, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return os.ReadFile(path)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := archive.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	for _, file := range archive.File {
+		if !cciListEntryPattern.MatchString(file.Name) {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(reader)
+		if closeErr := reader.Close(); err == nil {
+			err = closeErr
+		}
+		return data, err
+	}
+
+	return nil, fmt.Errorf("%s does not contain a CCI list", path)
+}
+
+// safeFileName replaces characters that cannot appear in file names
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,133 @@
+package cciparsing
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// CCI statuses that call for attention when a rule cites the CCI
+const (
+	StatusDeprecated = "deprecated"
+	StatusDraft      = "draft"
+)
+
+// cciReferencePattern matches CCI identifiers named in a CCI definition
+var cciReferencePattern = regexp.MustCompile(`CCI-\d{6}`)
+
+// StatusFinding records a rule citing a deprecated or draft CCI
+type StatusFinding struct {
+	STIGID string
+	RuleID string
+	CCI    string
+	Status string
+	// Replacements lists the CCIs the CCI list names in place of a deprecated CCI
+	Replacements []string
+}
+
+// Deprecated reports whether the CCI of the finding is deprecated
+func (f StatusFinding) Deprecated() bool {
+	return f.Status == StatusDeprecated
+}
+
+// CheckStatuses returns a finding for every rule of the checklist that cites a
+// deprecated or draft CCI, in checklist order
+func (x *CCIIndex) CheckStatuses(checklist *cklb.Checklist) []StatusFinding {
+	var findings []StatusFinding
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			for _, cci := range rule.CCIs {
+				item, ok := x.Get(cci)
+				if !ok {
+					continue
+				}
+				status := strings.ToLower(strings.TrimSpace(item.Status))
+				if status != StatusDeprecated && status != StatusDraft {
+					continue
+				}
+				finding := StatusFinding{STIGID: stig.STIGID, RuleID: rule.RuleID, CCI: cci, Status: status}
+				if status == StatusDeprecated {
+					finding.Replacements = x.Replacements(cci)
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// Replacements returns the CCIs that the definition of a deprecated CCI names,
+// such as "Deprecated, use CCI-000366", that are in the list and not deprecated themselves
+func (x *CCIIndex) Replacements(id string) []string {
+	item, ok := x.Get(id)
+	if !ok {
+		return nil
+	}
+
+	var replacements []string
+	seen := map[string]bool{id: true}
+	for _, candidate := range cciReferencePattern.FindAllString(item.Definition, -1) {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		if replacement, ok := x.Get(candidate); ok && !strings.EqualFold(replacement.Status, StatusDeprecated) {
+			replacements = append(replacements, candidate)
+		}
+	}
+	return replacements
+}
+
+// DescribeFinding formats a finding with the replacement control mapping, if the CCI list offers one
+func (x *CCIIndex) DescribeFinding(f StatusFinding) string {
+	description := fmt.Sprintf("rule %s cites %s %s", f.RuleID, f.Status, f.CCI)
+	if !f.Deprecated() {
+		return description
+	}
+
+	if len(f.Replacements) == 0 {
+		if controlID := x.ControlID(f.CCI); controlID != "" {
+			return fmt.Sprintf("%s (no replacement listed, still mapped to %s)", description, controlID)
+		}
+		return description + " (no replacement listed)"
+	}
+
+	var replacements []string
+	for _, replacement := range f.Replacements {
+		if controlID := x.ControlID(replacement); controlID != "" {
+			replacement += " -> " + controlID
+		}
+		replacements = append(replacements, replacement)
+	}
+	return fmt.Sprintf("%s, replaced by %s", description, strings.Join(replacements, ", "))
+}
+
+// ReportStatuses writes a warning for every rule citing a deprecated CCI and a note
+// for every rule citing a draft CCI, or only the number of those rules with
+// quietDraft. With failOnDeprecated, citing a deprecated CCI is an error.
+func (x *CCIIndex) ReportStatuses(w io.Writer, checklist *cklb.Checklist, quietDraft, failOnDeprecated bool) error {
+	deprecated := 0
+	draftRules := make(map[string]bool)
+	for _, finding := range x.CheckStatuses(checklist) {
+		if finding.Deprecated() {
+			deprecated++
+			fmt.Fprintf(w, "Warning: %s\n", x.DescribeFinding(finding))
+			continue
+		}
+		draftRules[finding.RuleID] = true
+		if !quietDraft {
+			fmt.Fprintf(w, "Note: %s\n", x.DescribeFinding(finding))
+		}
+	}
+
+	if len(draftRules) > 0 {
+		fmt.Fprintf(w, "Note: %d rules cite CCIs that are still in draft status\n", len(draftRules))
+	}
+	if deprecated > 0 && failOnDeprecated {
+		return fmt.Errorf("found %d citations of deprecated CCIs and --fail-on-deprecated-cci is set", deprecated)
+	}
+	return nil
+}
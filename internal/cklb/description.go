@@ -0,0 +1,131 @@
+package cklb
+
+import (
+	"strings"
+)
+
+// RuleDescription holds the fields DISA encodes as pseudo-XML inside an XCCDF rule description
+type RuleDescription struct {
+	VulnDiscussion           string
+	FalsePositives           string
+	FalseNegatives           string
+	Documentable             string
+	Mitigations              string
+	SeverityOverrideGuidance string
+	PotentialImpacts         string
+	ThirdPartyTools          string
+	MitigationControl        string
+	Responsibility           string
+	IAControls               string
+}
+
+// ParseRuleDescription splits a rule description such as
+// "<VulnDiscussion>...</VulnDiscussion><FalsePositives></FalsePositives>..."
+// into its fields. A description without any known tag is treated as the discussion.
+func ParseRuleDescription(description string) RuleDescription {
+	parsed, _ := parseRuleDescription(description)
+	return parsed
+}
+
+// parseRuleDescription parses a rule description and also returns the tags it
+// contains, with VulnDiscussion counted as present for an untagged description
+func parseRuleDescription(description string) (RuleDescription, map[string]bool) {
+	var parsed RuleDescription
+	present := make(map[string]bool)
+
+	for tag, field := range descriptionFields(&parsed) {
+		if value, ok := extractTag(description, tag); ok {
+			*field = value
+			present[tag] = true
+		}
+	}
+
+	if len(present) == 0 {
+		parsed.VulnDiscussion = strings.TrimSpace(description)
+		present["VulnDiscussion"] = true
+	}
+
+	return parsed, present
+}
+
+// descriptionFields maps each description tag to its field of a RuleDescription
+func descriptionFields(parsed *RuleDescription) map[string]*string {
+	return map[string]*string{
+		"VulnDiscussion":           &parsed.VulnDiscussion,
+		"FalsePositives":           &parsed.FalsePositives,
+		"FalseNegatives":           &parsed.FalseNegatives,
+		"Documentable":             &parsed.Documentable,
+		"Mitigations":              &parsed.Mitigations,
+		"SeverityOverrideGuidance": &parsed.SeverityOverrideGuidance,
+		"PotentialImpacts":         &parsed.PotentialImpacts,
+		"ThirdPartyTools":          &parsed.ThirdPartyTools,
+		"MitigationControl":        &parsed.MitigationControl,
+		"Responsibility":           &parsed.Responsibility,
+		"IAControls":               &parsed.IAControls,
+	}
+}
+
+// extractTag returns the content between <tag> and </tag>
+func extractTag(description, tag string) (string, bool) {
+	open := "<" + tag + ">"
+	start := strings.Index(description, open)
+	if start < 0 {
+		// An empty element may be written as <tag/> or <tag />
+		if strings.Contains(description, "<"+tag+"/>") || strings.Contains(description, "<"+tag+" />") {
+			return "", true
+		}
+		return "", false
+	}
+	start += len(open)
+
+	end := strings.Index(description[start:], "</"+tag+">")
+	if end < 0 {
+		return "", false
+	}
+
+	return strings.TrimSpace(description[start : start+end]), true
+}
+
+// ApplyDescription fills the rule's description fields from a pseudo-XML rule
+// description. Fields whose tag the description lacks keep their value.
+func (r *STIGRule) ApplyDescription(description string) {
+	parsed, present := parseRuleDescription(description)
+
+	targets := map[string]*string{
+		"VulnDiscussion":           &r.Discussion,
+		"FalsePositives":           &r.FalsePositives,
+		"FalseNegatives":           &r.FalseNegatives,
+		"Documentable":             &r.Documentable,
+		"Mitigations":              &r.Mitigations,
+		"SeverityOverrideGuidance": &r.SecurityOverrideGuidance,
+		"PotentialImpacts":         &r.PotentialImpacts,
+		"ThirdPartyTools":          &r.ThirdPartyTools,
+		"MitigationControl":        &r.MitigationControl,
+		"Responsibility":           &r.Responsibility,
+		"IAControls":               &r.IAControls,
+	}
+	for tag, value := range descriptionFields(&parsed) {
+		if present[tag] {
+			*targets[tag] = *value
+		}
+	}
+}
+
+// HasEncodedDescription reports whether the rule's discussion still holds an
+// unparsed pseudo-XML description
+func (r *STIGRule) HasEncodedDescription() bool {
+	return strings.Contains(r.Discussion, "<VulnDiscussion>")
+}
+
+// NormalizeDescriptions parses any rule discussion that still holds a
+// pseudo-XML description into the rule's description fields
+func (c *Checklist) NormalizeDescriptions() {
+	for i := range c.Data.STIGs {
+		for j := range c.Data.STIGs[i].Rules {
+			rule := &c.Data.STIGs[i].Rules[j]
+			if rule.HasEncodedDescription() {
+				rule.ApplyDescription(rule.Discussion)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package cklb
+
+import (
+	"testing"
+)
+
+func TestParseRuleDescription(t *testing.T) {
+	description := "<VulnDiscussion>Accounts must be managed.\n\nSecond paragraph.</VulnDiscussion>" +
+		"<FalsePositives></FalsePositives><Documentable>true</Documentable>" +
+		"<Mitigations>Use a directory.</Mitigations><IAControls/>"
+
+	parsed := ParseRuleDescription(description)
+	if parsed.VulnDiscussion != "Accounts must be managed.\n\nSecond paragraph." {
+		t.Errorf("VulnDiscussion = %q", parsed.VulnDiscussion)
+	}
+	if parsed.Documentable != "true" {
+		t.Errorf("Documentable = %q, expected true", parsed.Documentable)
+	}
+	if parsed.Mitigations != "Use a directory." {
+		t.Errorf("Mitigations = %q", parsed.Mitigations)
+	}
+
+	plain := ParseRuleDescription("No tags at all.")
+	if plain.VulnDiscussion != "No tags at all." {
+		t.Errorf("VulnDiscussion for untagged description = %q", plain.VulnDiscussion)
+	}
+}
+
+func TestApplyDescription(t *testing.T) {
+	rule := STIGRule{Responsibility: "System Administrator", Mitigations: "Existing mitigation", FalsePositives: "Old"}
+	rule.ApplyDescription("<VulnDiscussion>Accounts must be managed.</VulnDiscussion><FalsePositives></FalsePositives>")
+
+	if rule.Discussion != "Accounts must be managed." {
+		t.Errorf("Discussion = %q", rule.Discussion)
+	}
+	if rule.Responsibility != "System Administrator" || rule.Mitigations != "Existing mitigation" {
+		t.Errorf("Responsibility = %q and Mitigations = %q, expected the fields without tags to be kept", rule.Responsibility, rule.Mitigations)
+	}
+	if rule.FalsePositives != "" {
+		t.Errorf("FalsePositives = %q, expected the empty tag to clear it", rule.FalsePositives)
+	}
+}
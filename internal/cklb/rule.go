@@ -0,0 +1,52 @@
+package cklb
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Override is a rule property overridden in the checklist and the justification for it
+type Override struct {
+	Value  string
+	Reason string
+}
+
+// Override returns the override of a rule property, such as severity, if the rule has one
+func (r STIGRule) Override(property string) (Override, bool) {
+	if len(r.Overrides) == 0 {
+		return Override{}, false
+	}
+
+	var overrides map[string]map[string]string
+	if err := json.Unmarshal(r.Overrides, &overrides); err != nil {
+		return Override{}, false
+	}
+	entry, ok := overrides[property]
+	if !ok {
+		return Override{}, false
+	}
+
+	override := Override{Value: entry[property], Reason: entry["reason"]}
+	if override.Value == "" {
+		// Fall back to the first value, by key, that is not the justification
+		keys := make([]string, 0, len(entry))
+		for key := range entry {
+			if key != "reason" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			override.Value = entry[keys[0]]
+		}
+	}
+	return override, override.Value != ""
+}
+
+// EffectiveSeverity returns the severity of the rule with any override applied
+func (r STIGRule) EffectiveSeverity() string {
+	if override, ok := r.Override("severity"); ok {
+		return override.Value
+	}
+	return r.Severity
+}
@@ -37,6 +37,7 @@ type STIG struct {
 	DisplayName        string     `json:"display_name"`
 	STIGID             string     `json:"stig_id"`
 	ReleaseInfo        string     `json:"release_info"`
+	Version            string     `json:"version,omitempty"`
 	UUID               string     `json:"uuid"`
 	ReferenceIdentifier string     `json:"reference_identifier,omitempty"`
 	Size               int        `json:"size"`
@@ -76,6 +77,7 @@ type STIGRule struct {
 	CheckContentRef         *CheckContentRef `json:"check_content_ref,omitempty"`
 	LegacyIDs               []string      `json:"legacy_ids,omitempty"`
 	CCIs                    []string      `json:"ccis,omitempty"`
+	SRGID                   string        `json:"srg_id,omitempty"`
 	GroupTree               []GroupTree   `json:"group_tree,omitempty"`
 	CreatedAt               string        `json:"createdAt,omitempty"`
 	UpdatedAt               string        `json:"updatedAt,omitempty"`
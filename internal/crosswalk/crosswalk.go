@@ -0,0 +1,202 @@
+package crosswalk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NoRelationship marks mapping entries that state that no target control corresponds
+const NoRelationship = "no-relationship"
+
+// Crosswalk maps NIST SP 800-53 control ids to the controls of another framework
+type Crosswalk struct {
+	// Framework names the target framework, such as 800-171
+	Framework string
+	// Source identifies the target framework's catalog
+	Source  string
+	targets map[string][]string
+}
+
+// MappingCollection is the subset of an OSCAL mapping collection that crosswalks are read from
+type MappingCollection struct {
+	Mappings []Mapping `json:"mappings"`
+}
+
+// Mapping maps the controls of a source resource to those of a target resource
+type Mapping struct {
+	SourceResource Resource `json:"source-resource"`
+	TargetResource Resource `json:"target-resource"`
+	Maps           []Map    `json:"maps"`
+}
+
+// Resource identifies a catalog or profile taking part in a mapping
+type Resource struct {
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+	Href  string `json:"href"`
+}
+
+// Map relates source controls to target controls
+type Map struct {
+	Relationship string         `json:"relationship"`
+	Sources      []MapReference `json:"sources"`
+	Targets      []MapReference `json:"targets"`
+}
+
+// MapReference references a control or statement of a mapped resource
+type MapReference struct {
+	Type  string `json:"type"`
+	IDRef string `json:"id-ref"`
+}
+
+// LoadFromFile loads a crosswalk to the named framework from an OSCAL mapping
+// collection in JSON (.json) or a CSV file with source and target columns
+func LoadFromFile(path, framework string) (*Crosswalk, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var crosswalk *Crosswalk
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		crosswalk, err = ParseJSON(data, framework)
+	} else {
+		crosswalk, err = ParseCSV(bytes.NewReader(data), framework)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing crosswalk %s: %w", path, err)
+	}
+
+	if crosswalk.Source == "" {
+		crosswalk.Source = path
+	}
+	return crosswalk, nil
+}
+
+// ParseJSON parses an OSCAL mapping collection, with or without its
+// "mapping-collection" wrapper
+func ParseJSON(data []byte, framework string) (*Crosswalk, error) {
+	var document struct {
+		MappingCollection *MappingCollection `json:"mapping-collection"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	collection := document.MappingCollection
+	if collection == nil {
+		collection = &MappingCollection{}
+		if err := json.Unmarshal(data, collection); err != nil {
+			return nil, err
+		}
+	}
+	if len(collection.Mappings) == 0 {
+		return nil, fmt.Errorf("document contains no mappings")
+	}
+
+	crosswalk := New(framework)
+	for _, mapping := range collection.Mappings {
+		if crosswalk.Source == "" {
+			crosswalk.Source = mapping.TargetResource.Href
+		}
+		for _, m := range mapping.Maps {
+			if m.Relationship == NoRelationship {
+				continue
+			}
+			for _, source := range m.Sources {
+				for _, target := range m.Targets {
+					crosswalk.Add(source.IDRef, target.IDRef)
+				}
+			}
+		}
+	}
+	return crosswalk, nil
+}
+
+// ParseCSV parses a crosswalk from CSV with a header row naming a source column
+// (source, 800-53 or control) and a target column (target). An optional
+// relationship column skips rows marked no-relationship, and a target cell may
+// list several controls separated by semicolons.
+func ParseCSV(reader io.Reader, framework string) (*Crosswalk, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("crosswalk is empty")
+	}
+
+	sourceColumn, targetColumn, relationshipColumn := -1, -1, -1
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "source", "800-53", "nist 800-53", "control":
+			sourceColumn = i
+		case "target":
+			targetColumn = i
+		case "relationship":
+			relationshipColumn = i
+		}
+	}
+	if sourceColumn < 0 || targetColumn < 0 {
+		return nil, fmt.Errorf("crosswalk needs source and target columns")
+	}
+
+	crosswalk := New(framework)
+	for _, record := range records[1:] {
+		if sourceColumn >= len(record) || targetColumn >= len(record) {
+			continue
+		}
+		if relationshipColumn >= 0 && relationshipColumn < len(record) &&
+			strings.TrimSpace(record[relationshipColumn]) == NoRelationship {
+			continue
+		}
+		for _, target := range strings.Split(record[targetColumn], ";") {
+			crosswalk.Add(record[sourceColumn], target)
+		}
+	}
+	return crosswalk, nil
+}
+
+// New creates an empty crosswalk to the named framework
+func New(framework string) *Crosswalk {
+	return &Crosswalk{Framework: framework, targets: make(map[string][]string)}
+}
+
+// Add maps an 800-53 control id to a target control id
+func (c *Crosswalk) Add(source, target string) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	target = strings.TrimSpace(target)
+	if source == "" || target == "" {
+		return
+	}
+	for _, existing := range c.targets[source] {
+		if existing == target {
+			return
+		}
+	}
+	c.targets[source] = append(c.targets[source], target)
+}
+
+// Targets returns the target controls an 800-53 control id maps to
+func (c *Crosswalk) Targets(controlID string) []string {
+	return c.targets[strings.ToLower(controlID)]
+}
+
+// Sources returns every 800-53 control id the crosswalk maps, sorted
+func (c *Crosswalk) Sources() []string {
+	sources := make([]string, 0, len(c.targets))
+	for source := range c.targets {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
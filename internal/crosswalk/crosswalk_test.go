@@ -0,0 +1,72 @@
+package crosswalk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := `source,target,relationship
+AC-2,SP_800_171_03.01.01,subset-of
+ac-2,SP_800_171_03.01.01,subset-of
+cm-6,SP_800_171_03.04.01;SP_800_171_03.04.02,intersects-with
+pm-1,SP_800_171_03.15.01,no-relationship
+`
+	crosswalk, err := ParseCSV(strings.NewReader(input), "800-171")
+	if err != nil {
+		t.Fatalf("ParseCSV() returned error: %v", err)
+	}
+
+	if targets := crosswalk.Targets("ac-2"); len(targets) != 1 || targets[0] != "SP_800_171_03.01.01" {
+		t.Errorf("Targets(ac-2) = %v, expected [SP_800_171_03.01.01]", targets)
+	}
+	if targets := crosswalk.Targets("cm-6"); len(targets) != 2 {
+		t.Errorf("Targets(cm-6) = %v, expected two targets", targets)
+	}
+	if targets := crosswalk.Targets("pm-1"); len(targets) != 0 {
+		t.Errorf("Targets(pm-1) = %v, expected none", targets)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("a,b\n1,2\n"), "800-171"); err == nil {
+		t.Error("ParseCSV() without source and target columns returned no error")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	input := `{
+  "mapping-collection": {
+    "mappings": [
+      {
+        "source-resource": {"type": "catalog", "href": "https://example.com/800-53.json"},
+        "target-resource": {"type": "catalog", "href": "https://example.com/csf-2.0.json"},
+        "maps": [
+          {
+            "relationship": "subset-of",
+            "sources": [{"type": "control", "id-ref": "ac-2"}],
+            "targets": [{"type": "control", "id-ref": "pr.aa-05"}]
+          },
+          {
+            "relationship": "no-relationship",
+            "sources": [{"type": "control", "id-ref": "pm-1"}],
+            "targets": [{"type": "control", "id-ref": "gv.oc-01"}]
+          }
+        ]
+      }
+    ]
+  }
+}`
+	crosswalk, err := ParseJSON([]byte(input), "csf-2.0")
+	if err != nil {
+		t.Fatalf("ParseJSON() returned error: %v", err)
+	}
+
+	if crosswalk.Source != "https://example.com/csf-2.0.json" {
+		t.Errorf("Source = %s, expected the target resource href", crosswalk.Source)
+	}
+	if targets := crosswalk.Targets("AC-2"); len(targets) != 1 || targets[0] != "pr.aa-05" {
+		t.Errorf("Targets(AC-2) = %v, expected [pr.aa-05]", targets)
+	}
+	if sources := crosswalk.Sources(); len(sources) != 1 {
+		t.Errorf("Sources() = %v, expected [ac-2]", sources)
+	}
+}
@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// Catalog indexes the controls and parts of an OSCAL catalog
+type Catalog struct {
+	Catalog  *oscalTypes.Catalog
+	controls map[string]*oscalTypes.Control
+	parts    map[string]string
+}
+
+// LoadFromFile loads an OSCAL catalog in JSON format
+func LoadFromFile(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses an OSCAL catalog in JSON format, with or without the
+// top-level "catalog" wrapper
+func Parse(data []byte) (*Catalog, error) {
+	var document oscalTypes.OscalCompleteSchema
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	catalog := document.Catalog
+	if catalog == nil {
+		catalog = &oscalTypes.Catalog{}
+		if err := json.Unmarshal(data, catalog); err != nil {
+			return nil, err
+		}
+		if catalog.UUID == "" {
+			return nil, fmt.Errorf("document is not an OSCAL catalog")
+		}
+	}
+
+	return New(catalog), nil
+}
+
+// New indexes an OSCAL catalog
+func New(catalog *oscalTypes.Catalog) *Catalog {
+	c := &Catalog{
+		Catalog:  catalog,
+		controls: make(map[string]*oscalTypes.Control),
+		parts:    make(map[string]string),
+	}
+
+	if catalog.Controls != nil {
+		c.indexControls(*catalog.Controls)
+	}
+	if catalog.Groups != nil {
+		c.indexGroups(*catalog.Groups)
+	}
+
+	return c
+}
+
+func (c *Catalog) indexGroups(groups []oscalTypes.Group) {
+	for _, group := range groups {
+		if group.Controls != nil {
+			c.indexControls(*group.Controls)
+		}
+		if group.Groups != nil {
+			c.indexGroups(*group.Groups)
+		}
+	}
+}
+
+func (c *Catalog) indexControls(controls []oscalTypes.Control) {
+	for i := range controls {
+		control := &controls[i]
+		c.controls[control.ID] = control
+		if control.Parts != nil {
+			c.indexParts(control.ID, *control.Parts)
+		}
+		if control.Controls != nil {
+			c.indexControls(*control.Controls)
+		}
+	}
+}
+
+func (c *Catalog) indexParts(controlID string, parts []oscalTypes.Part) {
+	for _, part := range parts {
+		if part.ID != "" {
+			c.parts[part.ID] = controlID
+		}
+		if part.Parts != nil {
+			c.indexParts(controlID, *part.Parts)
+		}
+	}
+}
+
+// Control returns the control with the given id
+func (c *Catalog) Control(id string) (*oscalTypes.Control, bool) {
+	control, ok := c.controls[id]
+	return control, ok
+}
+
+// HasControl reports whether the catalog contains the control
+func (c *Catalog) HasControl(id string) bool {
+	_, ok := c.controls[id]
+	return ok
+}
+
+// HasPart reports whether the catalog contains a part, such as a statement, with the given id
+func (c *Catalog) HasPart(id string) bool {
+	_, ok := c.parts[id]
+	return ok
+}
+
+// Withdrawn reports whether the catalog marks the control as withdrawn
+func (c *Catalog) Withdrawn(id string) bool {
+	control, ok := c.controls[id]
+	if !ok || control.Props == nil {
+		return false
+	}
+	for _, prop := range *control.Props {
+		if prop.Name == "status" && prop.Value == "withdrawn" {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveControl returns the controls a control id stands for: the control itself,
+// or for a withdrawn control the controls its incorporated-into and moved-to links
+// point at, following withdrawn controls in turn. Links to statements resolve to
+// their control. It returns nil if neither the control nor a successor exists.
+func (c *Catalog) ResolveControl(id string) []string {
+	return c.resolveControl(id, make(map[string]bool))
+}
+
+func (c *Catalog) resolveControl(id string, visited map[string]bool) []string {
+	if visited[id] || !c.HasControl(id) {
+		return nil
+	}
+	visited[id] = true
+	if !c.Withdrawn(id) {
+		return []string{id}
+	}
+
+	var resolved []string
+	control := c.controls[id]
+	if control.Links == nil {
+		return nil
+	}
+	for _, link := range *control.Links {
+		if link.Rel != "incorporated-into" && link.Rel != "moved-to" {
+			continue
+		}
+		target := strings.TrimPrefix(link.Href, "#")
+		if controlID, ok := c.parts[target]; ok {
+			target = controlID
+		}
+		for _, successor := range c.resolveControl(target, visited) {
+			if !contains(resolved, successor) {
+				resolved = append(resolved, successor)
+			}
+		}
+	}
+	return resolved
+}
+
+// ResolveStatement returns the statement id itself if it exists in the
+// catalog, otherwise its closest existing parent such as ac-2_smt.a for
+// ac-2_smt.a.9 or ac-2_smt for ac-2_smt.z. It returns "" if no part of the
+// statement exists.
+func (c *Catalog) ResolveStatement(statementID string) string {
+	return c.resolvePart(statementID)
+}
+
+// ResolveObjective returns the assessment objective id, such as ac-2_obj.a, if it
+// exists in the catalog, otherwise its closest existing parent. If determination is
+// not zero and the objective is split into determination statements such as
+// ac-2_obj.a-1 and ac-2_obj.a-2, the numbered one is returned. It returns "" if
+// no part of the objective exists.
+func (c *Catalog) ResolveObjective(objectiveID string, determination int) string {
+	if determination > 0 {
+		if id := fmt.Sprintf("%s-%d", objectiveID, determination); c.HasPart(id) {
+			return id
+		}
+	}
+	return c.resolvePart(objectiveID)
+}
+
+// resolvePart walks up a dotted part id until a part that exists in the catalog
+func (c *Catalog) resolvePart(partID string) string {
+	for id := partID; id != ""; {
+		if c.HasPart(id) {
+			return id
+		}
+		i := strings.LastIndex(id, ".")
+		if i < 0 || !strings.Contains(id[:i], "_") {
+			break
+		}
+		id = id[:i]
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCatalogJSON = `{
+  "catalog": {
+    "uuid": "9f9a1e4c-1f7a-4a4e-9a4f-6e0e8a3b9d21",
+    "metadata": {
+      "title": "Test Catalog",
+      "last-modified": "2025-01-01T00:00:00Z",
+      "version": "1.0",
+      "oscal-version": "1.1.3"
+    },
+    "groups": [
+      {
+        "id": "ac",
+        "title": "Access Control",
+        "controls": [
+          {
+            "id": "ac-2",
+            "title": "Account Management",
+            "parts": [
+              {
+                "id": "ac-2_smt",
+                "name": "statement",
+                "parts": [
+                  {
+                    "id": "ac-2_smt.a",
+                    "name": "item"
+                  },
+                  {
+                    "id": "ac-2_smt.d",
+                    "name": "item",
+                    "parts": [
+                      {
+                        "id": "ac-2_smt.d.1",
+                        "name": "item"
+                      }
+                    ]
+                  }
+                ]
+              },
+              {
+                "id": "ac-2_obj",
+                "name": "assessment-objective",
+                "parts": [
+                  {
+                    "id": "ac-2_obj.a",
+                    "name": "assessment-objective",
+                    "parts": [
+                      {
+                        "id": "ac-2_obj.a-1",
+                        "name": "assessment-objective"
+                      },
+                      {
+                        "id": "ac-2_obj.a-2",
+                        "name": "assessment-objective"
+                      }
+                    ]
+                  }
+                ]
+              }
+            ],
+            "controls": [
+              {
+                "id": "ac-2.10",
+                "title": "Shared and Group Account Credential Change",
+                "props": [
+                  {
+                    "name": "status",
+                    "value": "withdrawn"
+                  }
+                ],
+                "links": [
+                  {
+                    "href": "#ac-2_smt.d",
+                    "rel": "incorporated-into"
+                  }
+                ]
+              },
+              {
+                "id": "ac-2.11",
+                "title": "Usage Conditions",
+                "props": [
+                  {
+                    "name": "status",
+                    "value": "withdrawn"
+                  }
+                ],
+                "links": [
+                  {
+                    "href": "#ac-2.10",
+                    "rel": "moved-to"
+                  },
+                  {
+                    "href": "#ac-2.4",
+                    "rel": "incorporated-into"
+                  }
+                ]
+              },
+              {
+                "id": "ac-2.4",
+                "title": "Automated Audit Actions",
+                "parts": [
+                  {
+                    "id": "ac-2.4_smt",
+                    "name": "statement"
+                  }
+                ]
+              }
+            ]
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func TestParse(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	if !catalog.HasControl("ac-2") || !catalog.HasControl("ac-2.4") {
+		t.Error("Parse() did not index controls ac-2 and ac-2.4")
+	}
+	if catalog.HasControl("ac-3") {
+		t.Error("HasControl(ac-3) = true, expected false")
+	}
+	if !catalog.HasPart("ac-2_smt.d.1") {
+		t.Error("HasPart(ac-2_smt.d.1) = false, expected true")
+	}
+
+	if _, err := Parse([]byte(`{"profile": {}}`)); err == nil {
+		t.Error("Parse() of a profile returned no error")
+	}
+}
+
+func TestResolveStatement(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"ac-2_smt.d.1", "ac-2_smt.d.1"},
+		{"ac-2_smt.a.9", "ac-2_smt.a"},
+		{"ac-2_smt.z", "ac-2_smt"},
+		{"ac-2.4_smt.a", "ac-2.4_smt"},
+		{"ac-3_smt.a", ""},
+	}
+
+	for _, tc := range testCases {
+		result := catalog.ResolveStatement(tc.input)
+		if result != tc.expected {
+			t.Errorf("ResolveStatement(%s) = %s, expected %s", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestResolveObjective(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	testCases := []struct {
+		input         string
+		determination int
+		expected      string
+	}{
+		{"ac-2_obj.a", 2, "ac-2_obj.a-2"},
+		{"ac-2_obj.a", 0, "ac-2_obj.a"},
+		{"ac-2_obj.a", 5, "ac-2_obj.a"},
+		{"ac-2_obj.d.1", 0, "ac-2_obj"},
+		{"ac-3_obj", 1, ""},
+	}
+
+	for _, tc := range testCases {
+		result := catalog.ResolveObjective(tc.input, tc.determination)
+		if result != tc.expected {
+			t.Errorf("ResolveObjective(%s, %d) = %s, expected %s", tc.input, tc.determination, result, tc.expected)
+		}
+	}
+}
+
+func TestResolveControl(t *testing.T) {
+	catalog, err := Parse([]byte(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	if !catalog.Withdrawn("ac-2.10") || catalog.Withdrawn("ac-2") {
+		t.Error("Withdrawn() did not report ac-2.10 as the only withdrawn control")
+	}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"ac-2", "ac-2"},
+		{"ac-2.10", "ac-2"},
+		{"ac-2.11", "ac-2,ac-2.4"},
+		{"ac-2.13", ""},
+	}
+
+	for _, tc := range testCases {
+		result := strings.Join(catalog.ResolveControl(tc.input), ",")
+		if result != tc.expected {
+			t.Errorf("ResolveControl(%s) = %s, expected %s", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	for _, revision := range []string{"4", "5"} {
+		catalog, err := Embedded(revision)
+		if err != nil {
+			t.Skipf("Embedded(%s) returned error: %v", revision, err)
+		}
+		if !catalog.HasControl("ac-2") || !catalog.HasControl("ac-2.13") || catalog.HasControl("ac-2.99") {
+			t.Errorf("revision %s catalog does not contain ac-2 and ac-2.13 alone", revision)
+		}
+	}
+	if catalog, err := Embedded("5"); err == nil && strings.Join(catalog.ResolveControl("ac-2.10"), ",") != "ac-2" {
+		t.Errorf("ResolveControl(ac-2.10) = %v, expected ac-2", catalog.ResolveControl("ac-2.10"))
+	}
+}
@@ -0,0 +1,23 @@
+package catalog
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:generate go run fetch_catalogs.go
+
+// embeddedFS holds the NIST SP 800-53 catalogs placed in assets at build time
+//
+//go:embed assets
+var embeddedFS embed.FS
+
+// Embedded loads the embedded NIST SP 800-53 catalog of a revision, 4 or 5
+func Embedded(revision string) (*Catalog, error) {
+	data, err := embeddedFS.ReadFile(fmt.Sprintf("assets/NIST_SP-800-53_rev%s_catalog.json", revision))
+	if err != nil {
+		return nil, fmt.Errorf("no NIST SP 800-53 revision %s catalog is embedded in this build, pass one with --catalog", revision)
+	}
+
+	return Parse(data)
+}
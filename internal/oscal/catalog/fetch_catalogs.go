@@ -0,0 +1,59 @@
+//go:build ignore
+
+// fetch_catalogs downloads NIST's SP 800-53 revision 4 and 5 catalogs from
+// usnistgov/oscal-content into assets, to be embedded in the oscalctl binary.
+// Run it with go generate, then commit the catalogs.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/open-automation-construct/oscalctl/internal/oscal/catalog"
+)
+
+const contentURL = "https://raw.githubusercontent.com/usnistgov/oscal-content/main/nist.gov/SP800-53/rev%s/json/NIST_SP-800-53_rev%s_catalog.json"
+
+func main() {
+	for _, revision := range []string{"4", "5"} {
+		if err := fetch(revision); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching the revision %s catalog: %v\n", revision, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func fetch(revision string) error {
+	url := fmt.Sprintf(contentURL, revision, revision)
+	response, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing response: %v\n", err)
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	controlCatalog, err := catalog.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s is not an OSCAL catalog: %w", url, err)
+	}
+
+	path := filepath.Join("assets", filepath.Base(url))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Fetched %s, version %s, into %s\n", controlCatalog.Catalog.Metadata.Title, controlCatalog.Catalog.Metadata.Version, path)
+	return nil
+}
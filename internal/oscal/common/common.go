@@ -5,15 +5,17 @@ import (
 	"path/filepath"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
-	"github.com/google/uuid"
 )
 
-func AddB64Resource(filePath string, data []byte, title, description string) (*oscalTypes.Resource, error) {
+// Namespace is the property namespace used for oscalctl specific props
+const Namespace = "https://github.com/open-automation-construct/oscalctl/ns/oscal"
+
+func AddB64Resource(uuid, filePath string, data []byte, title, description string) (*oscalTypes.Resource, error) {
 	
 	encodedContent := base64.StdEncoding.EncodeToString(data)
 
 	resource := &oscalTypes.Resource{
-		UUID:  uuid.New().String(),
+		UUID:  uuid,
 		Title: title,
 		Description: description,
 		Base64: &oscalTypes.Base64{
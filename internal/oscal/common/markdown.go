@@ -0,0 +1,48 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markdownEscaper escapes the characters that Markdown, and so OSCAL markup, would
+// otherwise read as inline formatting or HTML
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`|`, `\|`,
+)
+
+// blockMarkerPattern matches the start of a line Markdown would read as a heading,
+// list item or horizontal rule
+var blockMarkerPattern = regexp.MustCompile(`^(#+|[-+=~]|\d+[.)])(\s|$)`)
+
+// EscapeMarkdown escapes text so it is shown as-is when read as Markdown
+func EscapeMarkdown(text string) string {
+	escaped := markdownEscaper.Replace(text)
+	if loc := blockMarkerPattern.FindStringSubmatchIndex(escaped); loc != nil {
+		// Escape the last character of the marker: "#" becomes "\#", "1." becomes "1\."
+		end := loc[3] - 1
+		escaped = escaped[:end] + `\` + escaped[end:]
+	}
+	return escaped
+}
+
+// MarkdownParagraphs escapes each line of plain text, such as STIG check or fix text,
+// and makes it a paragraph of its own so line breaks survive Markdown rendering
+func MarkdownParagraphs(text string) string {
+	var paragraphs []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			paragraphs = append(paragraphs, EscapeMarkdown(line))
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
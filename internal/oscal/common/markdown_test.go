@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"$ sudo chage -l <account_name> | grep", `$ sudo chage -l \<account\_name\> \| grep`},
+		{"*.conf and [section]", `\*.conf and \[section\]`},
+		{"# PermitRootLogin no", `\# PermitRootLogin no`},
+		{"- item", `\- item`},
+		{"1. step", `1\. step`},
+		{"10) step", `10\) step`},
+		{"2024.1 release", "2024.1 release"},
+	}
+
+	for _, tc := range testCases {
+		if result := EscapeMarkdown(tc.input); result != tc.expected {
+			t.Errorf("EscapeMarkdown(%q) = %q, expected %q", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestMarkdownParagraphs(t *testing.T) {
+	input := "Verify with the following command:\r\n\r\n$ grep -i permitrootlogin /etc/ssh/sshd_config\nPermitRootLogin no\n\n"
+	expected := "Verify with the following command:\n\n$ grep -i permitrootlogin /etc/ssh/sshd\\_config\n\nPermitRootLogin no"
+	if result := MarkdownParagraphs(input); result != expected {
+		t.Errorf("MarkdownParagraphs() = %q, expected %q", result, expected)
+	}
+}
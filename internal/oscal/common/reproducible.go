@@ -0,0 +1,108 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UUIDGenerator issues the UUIDs of a generated OSCAL document. By default it issues
+// random (version 4) UUIDs. A name-based generator issues version 5 UUIDs derived
+// from its seed and the name of each object, so the same inputs always give the
+// same UUIDs. A nil generator issues random UUIDs.
+type UUIDGenerator struct {
+	namespace *uuid.UUID
+	issued    map[string]int
+	scope     []string
+}
+
+// NewNameBasedUUIDGenerator creates a generator of version 5 UUIDs within a
+// namespace derived from seed, such as the ids of the source documents
+func NewNameBasedUUIDGenerator(seed ...string) *UUIDGenerator {
+	namespace := uuid.NewSHA1(uuid.NameSpaceURL, []byte(Namespace+"/"+strings.Join(seed, "/")))
+	return &UUIDGenerator{namespace: &namespace, issued: make(map[string]int)}
+}
+
+// New returns a UUID for the object identified by names. A name-based generator
+// tells apart objects requested under the same names by the order they are requested in.
+func (g *UUIDGenerator) New(names ...string) string {
+	if g == nil || g.namespace == nil {
+		return uuid.New().String()
+	}
+
+	name := strings.Join(append(append([]string{}, g.scope...), names...), "/")
+	if n := g.issued[name]; n > 0 {
+		g.issued[name]++
+		name = fmt.Sprintf("%s#%d", name, n)
+	} else {
+		g.issued[name] = 1
+	}
+	return uuid.NewSHA1(*g.namespace, []byte(name)).String()
+}
+
+// Within returns a generator whose names are prefixed with names, such as the id of a
+// STIG, so objects of the same name in different scopes get different UUIDs
+func (g *UUIDGenerator) Within(names ...string) *UUIDGenerator {
+	if g == nil {
+		return nil
+	}
+	scope := append(append([]string{}, g.scope...), names...)
+	return &UUIDGenerator{namespace: g.namespace, issued: g.issued, scope: scope}
+}
+
+// SourceDateEpoch returns the time given by the SOURCE_DATE_EPOCH environment
+// variable, used by reproducible builds, and whether it is set
+func SourceDateEpoch() (time.Time, bool, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", value, err)
+	}
+	return time.Unix(seconds, 0).UTC(), true, nil
+}
+
+// LessID orders ids such as control or statement ids, comparing runs of digits
+// numerically so that ac-2 sorts before ac-10
+func LessID(a, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := isDigit(a[0]), isDigit(b[0])
+		if aDigits != bDigits {
+			return a < b
+		}
+
+		aRun, bRun := leadingRun(a, aDigits), leadingRun(b, bDigits)
+		if aRun != bRun {
+			if aDigits {
+				aNum, _ := strconv.ParseUint(aRun, 10, 64)
+				bNum, _ := strconv.ParseUint(bRun, 10, 64)
+				if aNum != bNum {
+					return aNum < bNum
+				}
+			}
+			return aRun < bRun
+		}
+		a, b = a[len(aRun):], b[len(bRun):]
+	}
+	return len(a) < len(b)
+}
+
+// leadingRun returns the leading run of digits or non-digits of s
+func leadingRun(s string, digits bool) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLessID(t *testing.T) {
+	ids := []string{"ac-10", "ac-2.12", "ac-2", "ac-2.3", "ac-2_smt.b", "ac-2_smt.a", "au-1", "ac-20"}
+	sort.Slice(ids, func(i, j int) bool { return LessID(ids[i], ids[j]) })
+
+	expected := "ac-2,ac-2.3,ac-2.12,ac-2_smt.a,ac-2_smt.b,ac-10,ac-20,au-1"
+	if got := strings.Join(ids, ","); got != expected {
+		t.Errorf("sorted ids = %s, expected %s", got, expected)
+	}
+}
+
+func TestNameBasedUUIDGenerator(t *testing.T) {
+	first := NewNameBasedUUIDGenerator("checklist", "stig")
+	second := NewNameBasedUUIDGenerator("checklist", "stig")
+
+	a, b := first.New("implemented-requirement", "ac-2"), second.New("implemented-requirement", "ac-2")
+	if a != b {
+		t.Errorf("UUIDs of the same name and seed differ: %s, %s", a, b)
+	}
+	if again := first.New("implemented-requirement", "ac-2"); again == a {
+		t.Error("a repeated name returned the same UUID")
+	}
+	if other := NewNameBasedUUIDGenerator("other").New("implemented-requirement", "ac-2"); other == a {
+		t.Error("UUIDs of different seeds are equal")
+	}
+
+	if scoped := first.Within("stig", "a").New("implemented-requirement", "ac-2"); scoped == a {
+		t.Error("a scoped generator returned the UUID of the unscoped name")
+	}
+	if first.Within("stig", "a").New("x") != second.Within("stig", "a").New("x") {
+		t.Error("UUIDs of the same scope and name differ")
+	}
+
+	var random *UUIDGenerator
+	if random.New("x") == random.New("x") {
+		t.Error("a nil generator returned the same UUID twice")
+	}
+}
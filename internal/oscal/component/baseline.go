@@ -0,0 +1,172 @@
+package component
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+)
+
+// Policies for implemented requirements of controls a profile's baseline does not select
+const (
+	// OutOfBaselineTag keeps them, marked with an out-of-baseline prop
+	OutOfBaselineTag = "tag"
+	// OutOfBaselineOmit leaves them out of the component definition
+	OutOfBaselineOmit = "omit"
+)
+
+// OutOfBaselinePolicies lists the supported out-of-baseline policies
+var OutOfBaselinePolicies = []string{OutOfBaselineTag, OutOfBaselineOmit}
+
+// validateOutOfBaselinePolicy checks the out-of-baseline policy
+func validateOutOfBaselinePolicy(policy string) error {
+	switch policy {
+	case OutOfBaselineTag, OutOfBaselineOmit:
+		return nil
+	default:
+		return fmt.Errorf("unknown out-of-baseline policy %q, expected one of %s", policy, strings.Join(OutOfBaselinePolicies, ", "))
+	}
+}
+
+// inBaseline reports whether the profile selects the control of a requirement, or any
+// 800-53 control its rules map to before a rollup or crosswalk. Requirements of a
+// catch-all control, whose rules map to no control, are always in the baseline.
+func inBaseline(controlReq *controlRequirement, opts generateOptions) bool {
+	if opts.profile == nil {
+		return true
+	}
+	if opts.crosswalk == nil && opts.profile.HasControl(controlReq.controlId) {
+		return true
+	}
+
+	catchAll := true
+	for _, contribution := range controlReq.rules {
+		if contribution.reason != "" {
+			continue
+		}
+		catchAll = false
+		if opts.profile.HasControl(contribution.control) {
+			return true
+		}
+	}
+	return catchAll
+}
+
+// outOfBaselineProp marks a requirement whose control the profile does not select
+func outOfBaselineProp(opts generateOptions) oscalTypes.Property {
+	return oscalTypes.Property{
+		Name:    "out-of-baseline",
+		Value:   "true",
+		Ns:      common.Namespace,
+		Remarks: fmt.Sprintf("Not selected by %s", opts.profile.Title()),
+	}
+}
+
+// baselineCoverage compares the 800-53 controls the checklist's rules map to with the
+// profile, returning the profile's controls no rule maps to, in profile order, and
+// the mapped controls the profile does not select
+func baselineCoverage(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions) (uncovered, outside []string) {
+	covered := make(map[string]bool)
+	requirements, _ := aggregateRequirements(checklist, cciIndex, opts)
+	for _, controlReq := range requirements {
+		for _, contribution := range controlReq.rules {
+			if contribution.reason == "" {
+				covered[strings.ToLower(contribution.control)] = true
+			}
+		}
+	}
+
+	for _, control := range opts.profile.Controls() {
+		if !covered[control] {
+			uncovered = append(uncovered, control)
+		}
+	}
+	for control := range covered {
+		if !opts.profile.HasControl(control) {
+			outside = append(outside, control)
+		}
+	}
+	sort.Slice(outside, func(i, j int) bool {
+		return common.LessID(outside[i], outside[j])
+	})
+	return uncovered, outside
+}
+
+// baselineReportPath returns the report path to use, deriving one from the
+// component output path if none is configured
+func baselineReportPath(reportPath, outputPath string) string {
+	if reportPath != "" {
+		return reportPath
+	}
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-baseline-gaps.csv"
+}
+
+// writeBaselineReport writes one CSV row per baseline control no rule covers, titled
+// from the catalog if one was loaded
+func writeBaselineReport(uncovered []string, opts generateOptions, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	w := csv.NewWriter(file)
+	if err := w.Write([]string{"control_id", "control_title"}); err != nil {
+		return err
+	}
+	for _, control := range uncovered {
+		title := ""
+		if opts.catalog != nil {
+			if catalogControl, ok := opts.catalog.Control(control); ok {
+				title = catalogControl.Title
+			}
+		}
+		if err := w.Write([]string{control, title}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// reportBaseline prints how much of the profile's baseline the checklist covers and
+// writes the baseline controls no rule covers to a CSV report
+func reportBaseline(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions, outputPath string) error {
+	if opts.profile == nil {
+		return nil
+	}
+
+	uncovered, outside := baselineCoverage(checklist, cciIndex, opts)
+	controls := opts.profile.Controls()
+	fmt.Printf("%d of %d controls of %s are covered by at least one rule\n", len(controls)-len(uncovered), len(controls), opts.profile.Title())
+	if !opts.profile.Enumerable() {
+		fmt.Println("Warning: the profile also selects controls by pattern, child controls or all controls, which are not counted")
+	}
+	if len(outside) > 0 {
+		fmt.Printf("%d mapped controls are outside the baseline (policy: %s): %s\n", len(outside), opts.outOfBaseline, strings.Join(outside, ", "))
+	}
+
+	path := baselineReportPath(opts.baselineReport, outputPath)
+	if err := writeBaselineReport(uncovered, opts, path); err != nil {
+		return fmt.Errorf("failed to write baseline report: %w", err)
+	}
+	fmt.Printf("Wrote %d uncovered baseline controls to %s\n", len(uncovered), path)
+	return nil
+}
@@ -0,0 +1,148 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/catalog"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+)
+
+// loadCatalog loads the catalog used to check control and statement ids: the one
+// given with --catalog or oscal.component.catalog, or else the embedded catalog of
+// the revision. Builds without an embedded catalog skip the check with a warning.
+func loadCatalog(revision string) (*catalog.Catalog, error) {
+	if catalogPath := viper.GetString("oscal.component.catalog"); catalogPath != "" {
+		controlCatalog, err := catalog.LoadFromFile(catalogPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load catalog: %w", err)
+		}
+		return controlCatalog, nil
+	}
+
+	controlCatalog, err := catalog.Embedded(revision)
+	if err != nil {
+		fmt.Printf("Warning: control and statement ids are not checked: %v\n", err)
+		return nil, nil
+	}
+	return controlCatalog, nil
+}
+
+// resolveControl returns the controls a control id mapped from a CCI stands for in
+// the catalog: the control itself, or those a withdrawn control was incorporated
+// into. Without a catalog, or if the catalog cannot resolve it, the id is kept.
+func resolveControl(control string, controlCatalog *catalog.Catalog) []string {
+	if controlCatalog == nil {
+		return []string{control}
+	}
+	if resolved := controlCatalog.ResolveControl(control); len(resolved) > 0 {
+		return resolved
+	}
+	return []string{control}
+}
+
+// controlIDFinding is a control or statement id of the generated component definition
+// that the catalog does not contain as is
+type controlIDFinding struct {
+	// kind is control or statement
+	kind string
+	id   string
+	// resolved is what the id resolves to in the catalog: the successors of a
+	// withdrawn control or the closest existing statement, empty if nothing
+	resolved []string
+	ccis     []string
+	rules    []string
+}
+
+// checkControlIDs checks the control and statement ids the CCIs of the checklist map
+// to, and the catch-all control of the unmapped rule policy, against the catalog
+func checkControlIDs(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions) []*controlIDFinding {
+	if opts.catalog == nil || cciIndex == nil {
+		return nil
+	}
+
+	var findings []*controlIDFinding
+	byID := make(map[string]*controlIDFinding)
+	record := func(kind, id string, resolved []string, cci, ruleID string) {
+		key := kind + " " + id
+		finding, exists := byID[key]
+		if !exists {
+			finding = &controlIDFinding{kind: kind, id: id, resolved: resolved}
+			byID[key] = finding
+			findings = append(findings, finding)
+		}
+		if cci != "" {
+			finding.ccis = appendUnique(finding.ccis, cci)
+		}
+		if ruleID != "" {
+			finding.rules = appendUnique(finding.rules, ruleID)
+		}
+	}
+
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			for _, cci := range extractCCINumbers(rule) {
+				control := cciIndex.ControlID(cci)
+				if control == "" {
+					continue
+				}
+				if !opts.catalog.HasControl(control) || opts.catalog.Withdrawn(control) {
+					record("control", control, opts.catalog.ResolveControl(control), cci, rule.RuleID)
+					continue
+				}
+				if statementID := cciIndex.StatementID(cci); statementID != "" && !opts.catalog.HasPart(statementID) {
+					var resolved []string
+					if closest := opts.catalog.ResolveStatement(statementID); closest != "" {
+						resolved = []string{closest}
+					}
+					record("statement", statementID, resolved, cci, rule.RuleID)
+				}
+			}
+		}
+	}
+
+	if opts.unmappedPolicy == UnmappedControl && opts.unmappedControl != "" && !opts.catalog.HasControl(opts.unmappedControl) {
+		record("control", opts.unmappedControl, nil, "", "")
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].kind != findings[j].kind {
+			return findings[i].kind < findings[j].kind
+		}
+		return common.LessID(findings[i].id, findings[j].id)
+	})
+	return findings
+}
+
+// reportControlIDs prints the control and statement ids the catalog does not contain
+// as is and how they are resolved. Withdrawn controls are replaced by the controls
+// they were incorporated into and statements by their closest existing parent; ids
+// that resolve to nothing are kept and fail generation if failOnUnresolved is set.
+func reportControlIDs(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions, failOnUnresolved bool) error {
+	unresolved := 0
+	for _, finding := range checkControlIDs(checklist, cciIndex, opts) {
+		source := ""
+		if len(finding.ccis) > 0 {
+			source = fmt.Sprintf(" (%s; rules %s)", strings.Join(finding.ccis, ", "), strings.Join(finding.rules, ", "))
+		}
+		switch {
+		case finding.kind == "control" && len(finding.resolved) > 0:
+			fmt.Printf("Note: control %s is withdrawn, using %s instead%s\n", finding.id, strings.Join(finding.resolved, ", "), source)
+		case finding.kind == "statement" && len(finding.resolved) > 0:
+			fmt.Printf("Note: statement %s is not in the catalog, using %s instead%s\n", finding.id, finding.resolved[0], source)
+		default:
+			unresolved++
+			fmt.Printf("Warning: %s %s is not in the catalog%s\n", finding.kind, finding.id, source)
+		}
+	}
+
+	if unresolved > 0 && failOnUnresolved {
+		return fmt.Errorf("found %d control or statement ids that are not in the catalog and --fail-on-unresolved-control is set", unresolved)
+	}
+	return nil
+}
@@ -5,18 +5,66 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/spf13/viper"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 
 	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
 	"github.com/open-automation-construct/oscalctl/internal/cklb"
+    "github.com/open-automation-construct/oscalctl/internal/crosswalk"
+    "github.com/open-automation-construct/oscalctl/internal/oscal/catalog"
     "github.com/open-automation-construct/oscalctl/internal/oscal/common"
+    "github.com/open-automation-construct/oscalctl/internal/oscal/profile"
 )
 
+// catalogSources are the NIST SP 800-53 catalogs control implementations refer to, by revision
+var catalogSources = map[string]string{
+	"5": "https://raw.githubusercontent.com/usnistgov/oscal-content/main/nist.gov/SP800-53/rev5/json/NIST_SP-800-53_rev5_catalog.json",
+	"4": "https://raw.githubusercontent.com/usnistgov/oscal-content/main/nist.gov/SP800-53/rev4/json/NIST_SP-800-53_rev4_catalog.json",
+}
+
+// generateOptions holds the settings that shape the generated component definition
+type generateOptions struct {
+	// catalog is used to check statement ids, if set
+	catalog *catalog.Catalog
+	// revision is the NIST SP 800-53 revision CCIs are mapped to
+	revision string
+	// fallback allows CCIs without a reference to revision to use other references
+	fallback bool
+	// crosswalk rekeys requirements to another framework, if set
+	crosswalk *crosswalk.Crosswalk
+	// rollupEnhancements aggregates control enhancements under their base control
+	rollupEnhancements bool
+	// unmappedPolicy decides what happens to rules that map to no control
+	unmappedPolicy string
+	// unmappedControl is the catch-all control of the UnmappedControl policy
+	unmappedControl string
+	// unmappedReport is the report file of the UnmappedReport policy
+	unmappedReport string
+	// uuids issues the UUIDs of the component definition, random ones if nil
+	uuids *common.UUIDGenerator
+	// lastModified is the last-modified time of the component definition, now if zero
+	lastModified time.Time
+	// benchmarks maps STIG keys to the UUIDs of their benchmark back-matter resources
+	benchmarks map[string]string
+	// statusMapping maps checklist statuses to implementation statuses, the defaults if nil
+	statusMapping map[string]string
+	// capability groups the components of the STIGs under a capability
+	capability bool
+	// metadata sets the components' type, purpose and responsible parties
+	metadata componentMetadata
+	// profile is the baseline control implementations refer to, if set
+	profile *profile.Profile
+	// outOfBaseline decides what happens to requirements of controls outside the profile
+	outOfBaseline string
+	// baselineReport is the report file of baseline controls no rule covers
+	baselineReport string
+}
+
 func GenerateComponent(inputPath, outputPath, cciPath string) error {
 	// Read and parse the input STIG checklist
 	checklist, err := readSTIGChecklist(inputPath)
@@ -24,27 +72,153 @@ func GenerateComponent(inputPath, outputPath, cciPath string) error {
 		return fmt.Errorf("failed to read STIG checklist: %w", err)
 	}
 
-	// Parse CCI document - this will use the embedded one if cciPath is empty
-	cciControlMap, err := cciparsing.ParseCCIDocument(cciPath)
+	// Parse CCI document - this will use the imported or embedded one if cciPath is empty
+	cciIndex, err := cciparsing.LoadCCIIndex(cciPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse CCI document: %w", err)
 	}
 
+	// Map CCIs to the selected NIST SP 800-53 revision
+	opts := generateOptions{
+		revision: viper.GetString("oscal.component.nistRevision"),
+		fallback: !viper.GetBool("oscal.component.noFallback"),
+		rollupEnhancements: viper.GetBool("oscal.component.rollupEnhancements"),
+		unmappedPolicy: viper.GetString("oscal.component.unmappedPolicy"),
+		unmappedControl: viper.GetString("oscal.component.unmappedControl"),
+		unmappedReport: viper.GetString("oscal.component.unmappedReport"),
+		capability: viper.GetBool("oscal.component.capability"),
+		outOfBaseline: viper.GetString("oscal.component.outOfBaseline"),
+		baselineReport: viper.GetString("oscal.component.baselineReport"),
+	}
+	if opts.revision == "" {
+		opts.revision = "5"
+	}
+	if opts.unmappedPolicy == "" {
+		opts.unmappedPolicy = UnmappedOmit
+	}
+	if err := validateUnmappedPolicy(opts.unmappedPolicy, opts.unmappedControl); err != nil {
+		return err
+	}
+	if opts.outOfBaseline == "" {
+		opts.outOfBaseline = OutOfBaselineTag
+	}
+	if err := validateOutOfBaselinePolicy(opts.outOfBaseline); err != nil {
+		return err
+	}
+	if err := setReproducibility(&opts, checklist, viper.GetBool("oscal.component.reproducible")); err != nil {
+		return err
+	}
+	if opts.metadata, err = loadComponentMetadata(opts.uuids); err != nil {
+		return err
+	}
+	if opts.statusMapping, err = statusMapping(viper.GetStringMapString("oscal.component.statusMapping")); err != nil {
+		return err
+	}
+	preference, err := cciparsing.PreferenceForRevision(opts.revision, opts.fallback)
+	if err != nil {
+		return err
+	}
+	cciIndex.SetPreference(preference)
+	if err := applyCCIOverrides(cciIndex); err != nil {
+		return err
+	}
+	reportUnmappedCCIs(checklist, cciIndex)
+	if err := cciIndex.ReportStatuses(os.Stdout, checklist, viper.GetBool("oscal.component.quietDraft"), viper.GetBool("oscal.component.failOnDeprecatedCCI")); err != nil {
+		return err
+	}
+
+	// Load the crosswalk to the target framework
+	if opts.crosswalk, err = loadCrosswalk(viper.GetString("oscal.component.framework")); err != nil {
+		return err
+	}
+
+	// Load the catalog used to check control and statement ids
+	if opts.catalog, err = loadCatalog(opts.revision); err != nil {
+		return err
+	}
+	if err := reportControlIDs(checklist, cciIndex, opts, viper.GetBool("oscal.component.failOnUnresolvedControl")); err != nil {
+		return err
+	}
+
+	// Load the profile whose baseline control implementations refer to, if one was given
+	if profilePath := viper.GetString("oscal.component.profile"); profilePath != "" {
+		opts.profile, err = profile.LoadFromFile(profilePath)
+		if err != nil {
+			return fmt.Errorf("failed to load profile: %w", err)
+		}
+	}
+
 	// Generate OSCAL component - pass the inputPath to createComponent
-	component, err := createComponent(checklist, cciControlMap, inputPath)
+	component, unmapped, err := createComponent(checklist, cciIndex, opts, inputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create OSCAL component: %w", err)
 	}
 
+	// Fail on or report rules without a control mapping, as configured
+	if err := handleUnmappedRules(unmapped, opts, outputPath); err != nil {
+		return err
+	}
+
+	// Report the baseline controls no rule covers
+	if err := reportBaseline(checklist, cciIndex, opts, outputPath); err != nil {
+		return err
+	}
+
 	// Output the component
 	if err := writeComponent(component, outputPath); err != nil {
 		return fmt.Errorf("failed to write OSCAL component: %w", err)
 	}
 
+	summarizeUnmappedRules(unmapped, opts)
 	fmt.Printf("Generated OSCAL component at %s\n", outputPath)
 	return nil
 }
 
+// setReproducibility sets the last-modified time from SOURCE_DATE_EPOCH if it is set.
+// In reproducible mode, UUIDs are derived from the checklist and STIG ids, and without
+// SOURCE_DATE_EPOCH the last-modified time is the checklist's latest update.
+func setReproducibility(opts *generateOptions, checklist *cklb.Checklist, reproducible bool) error {
+	sourceDate, ok, err := common.SourceDateEpoch()
+	if err != nil {
+		return err
+	}
+	if ok {
+		opts.lastModified = sourceDate
+	}
+	if !reproducible {
+		return nil
+	}
+
+	// Objects of a STIG are scoped by its UUID, so adding or removing a STIG
+	// leaves the UUIDs of the others unchanged
+	opts.uuids = common.NewNameBasedUUIDGenerator(checklist.Data.ID)
+
+	if opts.lastModified.IsZero() {
+		opts.lastModified = latestChecklistUpdate(checklist)
+		if opts.lastModified.IsZero() {
+			fmt.Println("Warning: the checklist has no update times and SOURCE_DATE_EPOCH is not set, using the Unix epoch as last-modified time")
+			opts.lastModified = time.Unix(0, 0).UTC()
+		}
+	}
+	return nil
+}
+
+// latestChecklistUpdate returns the latest update time of the checklist's rules, or
+// the zero time if none can be parsed
+func latestChecklistUpdate(checklist *cklb.Checklist) time.Time {
+	var latest time.Time
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			for _, value := range []string{rule.UpdatedAt, rule.CreatedAt} {
+				if t, err := time.Parse(time.RFC3339, value); err == nil && t.After(latest) {
+					latest = t.UTC()
+				}
+			}
+		}
+	}
+	return latest
+}
+
 // readSTIGChecklist reads and parses a STIG checklist file
 func readSTIGChecklist(path string) (*cklb.Checklist, error) {
 	checklist := &cklb.Checklist{}
@@ -61,9 +235,115 @@ func readSTIGChecklist(path string) (*cklb.Checklist, error) {
 		return nil, fmt.Errorf("checklist validation failed: %v", errors)
 	}
 
+	// Split any pseudo-XML rule descriptions into their fields
+	checklist.NormalizeDescriptions()
+
 	return checklist, nil
 }
 
+// applyCCIOverrides maps CCIs to the controls given in the oscal.component.cciOverrides
+// configuration and in the overrides file, which takes precedence
+func applyCCIOverrides(cciIndex *cciparsing.CCIIndex) error {
+	overrides := make(map[string]string)
+	// Configuration keys are lower cased, CCI ids are not
+	for cci, control := range viper.GetStringMapString("oscal.component.cciOverrides") {
+		if err := cciparsing.ValidateOverride(control); err != nil {
+			return fmt.Errorf("invalid CCI override of %s: %w", strings.ToUpper(cci), err)
+		}
+		overrides[strings.ToUpper(cci)] = control
+	}
+	if path := viper.GetString("oscal.component.cciOverridesFile"); path != "" {
+		fileOverrides, err := cciparsing.LoadOverrides(path)
+		if err != nil {
+			return fmt.Errorf("failed to load CCI overrides: %w", err)
+		}
+		for cci, control := range fileOverrides {
+			overrides[cci] = control
+		}
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	
+	if unknown := cciIndex.SetOverrides(overrides); len(unknown) > 0 {
+		fmt.Printf("Warning: ignoring overrides of CCIs not in the CCI list: %s\n", strings.Join(unknown, ", "))
+	}
+	fmt.Printf("Applied %d CCI overrides\n", len(overrides))
+	return nil
+}
+
+// loadCrosswalk loads the crosswalk to framework from the crosswalk flag or the
+// oscal.component.crosswalks configuration. NIST SP 800-53 itself needs none.
+func loadCrosswalk(framework string) (*crosswalk.Crosswalk, error) {
+	if framework == "" || strings.EqualFold(framework, "800-53") {
+		return nil, nil
+	}
+	
+	path := viper.GetString("oscal.component.crosswalk")
+	if path == "" {
+		path = viper.GetStringMapString("oscal.component.crosswalks")[strings.ToLower(framework)]
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no crosswalk given for framework %s, use --crosswalk or oscal.component.crosswalks", framework)
+	}
+	
+	cw, err := crosswalk.LoadFromFile(path, framework)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load crosswalk: %w", err)
+	}
+	fmt.Printf("Mapping %d NIST SP 800-53 controls to %s with crosswalk %s\n", len(cw.Sources()), framework, path)
+	return cw, nil
+}
+
+// reportUnmappedCCIs lists the checklist CCIs that have no control under the selected
+// references, separating those missing from the CCI list
+func reportUnmappedCCIs(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex) {
+	seen := make(map[string]bool)
+	var ccis []string
+	for _, stig := range checklist.Data.STIGs {
+		for _, rule := range stig.Rules {
+			for _, cci := range extractCCINumbers(rule) {
+				if !seen[cci] {
+					seen[cci] = true
+					ccis = append(ccis, cci)
+				}
+			}
+		}
+	}
+	
+	// Unmapped CCIs are either missing from the CCI list or have no reference
+	// to the selected revision, as in the reasons of unmapped rules
+	var unknown, unreferenced []string
+	for _, cci := range cciIndex.Unmapped(ccis) {
+		if _, exists := cciIndex.Get(cci); exists {
+			unreferenced = append(unreferenced, cci)
+		} else {
+			unknown = append(unknown, cci)
+		}
+	}
+
+	if len(unknown) > 0 {
+		list := "the CCI list"
+		if cciIndex.Version != "" {
+			list = fmt.Sprintf("CCI list %s", cciIndex.Version)
+		}
+		fmt.Printf("Warning: %d of %d CCIs are not in %s:\n", len(unknown), len(ccis), list)
+		for _, cci := range unknown {
+			fmt.Printf("  - %s\n", cci)
+		}
+	}
+	if len(unreferenced) > 0 {
+		var references []string
+		for _, pref := range cciIndex.Preference() {
+			references = append(references, pref.String())
+		}
+		fmt.Printf("Warning: %d of %d CCIs have no reference to %s:\n", len(unreferenced), len(ccis), strings.Join(references, ", "))
+		for _, cci := range unreferenced {
+			fmt.Printf("  - %s\n", cci)
+		}
+	}
+}
+
 // extractCCINumbers extracts CCI identifiers from a rule
 func extractCCINumbers(rule cklb.STIGRule) []string {
 	var cciNumbers []string
@@ -79,10 +359,9 @@ func extractCCINumbers(rule cklb.STIGRule) []string {
 	return cciNumbers
 }
 
-func createComponent(checklist *cklb.Checklist, cciControlMap map[string]string, inputPath string) (*oscalTypes.ComponentDefinition, error) {
+func createComponent(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions, inputPath string) (*oscalTypes.ComponentDefinition, []unmappedRule, error) {
 	// Generate UUIDs
-	componentDefUUID := uuid.New().String()
-	componentUUID := uuid.New().String()
+	componentDefUUID := opts.uuids.New("component-definition")
 	
 	// Get custom title if specified, otherwise use checklist title
 	customTitle := viper.GetString("oscal.title")
@@ -92,31 +371,66 @@ func createComponent(checklist *cklb.Checklist, cciControlMap map[string]string,
 	}
 	
 	// Build metadata
-	lastModified := time.Now()
+	lastModified := opts.lastModified
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+	version := opts.metadata.version
+	if version == "" {
+		version = defaultDocumentVersion
+	}
 	metadata := oscalTypes.Metadata{
 		Title: title,
 		LastModified: lastModified,
-		Version: "1.0.0",
+		Version: version,
 		OscalVersion: "1.1.3",
+		Props: &[]oscalTypes.Property{
+			{Name: "nist-800-53-revision", Value: opts.revision, Ns: common.Namespace},
+			{Name: "cci-reference-fallback", Value: fmt.Sprintf("%t", opts.fallback), Ns: common.Namespace},
+		},
 	}
-	
-	// Build the component
-	definedComponent := oscalTypes.DefinedComponent{
-		UUID: componentUUID,
-		Type: "software",
-		Title: title,
-		Description: fmt.Sprintf("Component definition generated from Checklist: %s", checklist.Data.Title),
+	if cciIndex != nil && cciIndex.Version != "" {
+		*metadata.Props = append(*metadata.Props, oscalTypes.Property{Name: "cci-list-version", Value: cciIndex.Version, Ns: common.Namespace})
+	}
+	if opts.crosswalk != nil {
+		*metadata.Props = append(*metadata.Props, oscalTypes.Property{Name: "framework", Value: opts.crosswalk.Framework, Ns: common.Namespace})
+	}
+	if opts.profile != nil {
+		*metadata.Props = append(*metadata.Props, oscalTypes.Property{Name: "baseline", Value: opts.profile.Title(), Ns: common.Namespace})
+	}
+	if len(opts.metadata.roles) > 0 {
+		metadata.Roles = &opts.metadata.roles
+	}
+	if len(opts.metadata.parties) > 0 {
+		metadata.Parties = &opts.metadata.parties
 	}
+	if len(opts.metadata.locations) > 0 {
+		metadata.Locations = &opts.metadata.locations
+	}
+	
+	// Describe each STIG benchmark in the back-matter for requirements to link to
+	resources, benchmarks := benchmarkResources(checklist, opts.uuids)
+	opts.benchmarks = benchmarks
 	
-	// Set control implementation sets
-	controlImplementationSets := buildControlImplementationSets(checklist, cciControlMap)
-	definedComponent.ControlImplementations = &controlImplementationSets
+	// Build one component per STIG, each with its own control implementations
+	var components []oscalTypes.DefinedComponent
+	var unmapped []unmappedRule
+	for i := range checklist.Data.STIGs {
+		definedComponent, stigUnmapped := buildSTIGComponent(checklist, i, cciIndex, opts)
+		components = append(components, definedComponent)
+		unmapped = append(unmapped, stigUnmapped...)
+	}
 	
 	// Create the component definition
 	component := &oscalTypes.ComponentDefinition{
 		UUID: componentDefUUID,
 		Metadata: metadata,
-		Components: &[]oscalTypes.DefinedComponent{definedComponent},
+		Components: &components,
+	}
+	
+	// Group the components under a capability named after the checklist
+	if opts.capability && len(components) > 0 {
+		component.Capabilities = &[]oscalTypes.Capability{buildCapability(title, checklist, components, opts)}
 	}
 
 	// Get the raw JSON data of the checklist for the back-matter resource
@@ -126,78 +440,512 @@ func createComponent(checklist *cklb.Checklist, cciControlMap map[string]string,
 	} else {
 		// Add the checklist as a back-matter resource using the already loaded data
 		if resource, err := common.AddB64Resource(
+			opts.uuids.New("resource", "checklist"),
 			inputPath,
 			checklistJSON,
 			"Original STIG Checklist",
 			"Base64 encoded CKLB(json) STIG checklist used to generate this component definition",
 		); err == nil {
-			// Initialize back-matter with the resource
-			backMatter := oscalTypes.BackMatter{
-				Resources: &[]oscalTypes.Resource{*resource},
-			}
-			component.BackMatter = &backMatter
+			resources = append([]oscalTypes.Resource{*resource}, resources...)
 		} else {
 			// Log the error but don't fail the whole operation
 			fmt.Printf("Warning: Failed to add checklist as back-matter resource: %v\n", err)
 		}
 	}
+	
+	if len(resources) > 0 {
+		component.BackMatter = &oscalTypes.BackMatter{Resources: &resources}
+	}
+
+	return component, unmapped, nil
+}
+
+// buildSTIGComponent builds the component of the STIG at index i of the checklist,
+// returning the STIG's rules that map to no control
+func buildSTIGComponent(checklist *cklb.Checklist, i int, cciIndex *cciparsing.CCIIndex, opts generateOptions) (oscalTypes.DefinedComponent, []unmappedRule) {
+	stig := checklist.Data.STIGs[i]
+	title := stigTitle(&stig)
+	
+	scope := stig.UUID
+	if scope == "" {
+		scope = stigKey(&stig)
+	}
+	opts.uuids = opts.uuids.Within("stig", scope)
+	
+	// Build the control implementations from the STIG alone
+	stigChecklist := &cklb.Checklist{Data: checklist.Data}
+	stigChecklist.Data.Title = title
+	stigChecklist.Data.STIGs = checklist.Data.STIGs[i : i+1]
+	controlImplementationSets, unmapped := buildControlImplementationSets(stigChecklist, cciIndex, opts)
+	
+	componentType := opts.metadata.componentType
+	if componentType == "" {
+		componentType = defaultComponentType
+	}
+	definedComponent := oscalTypes.DefinedComponent{
+		UUID: opts.uuids.New("component"),
+		Type: componentType,
+		Title: title,
+		Description: fmt.Sprintf("Component generated from %s in Checklist: %s", stig.STIGName, checklist.Data.Title),
+		Purpose: opts.metadata.purpose,
+		ControlImplementations: &controlImplementationSets,
+	}
+	
+	if props := stigProps(&stig); len(props) > 0 {
+		definedComponent.Props = &props
+	}
+	if len(opts.metadata.protocols) > 0 {
+		protocols := componentProtocols(opts.metadata.protocols, opts.uuids)
+		definedComponent.Protocols = &protocols
+	}
+	if len(opts.metadata.responsibleRoles) > 0 {
+		responsibleRoles := append([]oscalTypes.ResponsibleRole(nil), opts.metadata.responsibleRoles...)
+		definedComponent.ResponsibleRoles = &responsibleRoles
+	}
+	
+	return definedComponent, unmapped
+}
 
-	return component, nil
+// stigTitle titles the component of a STIG from its display name and release
+func stigTitle(stig *cklb.STIG) string {
+	title := stig.DisplayName
+	if title == "" {
+		title = stig.STIGName
+	}
+	if release := strings.TrimSpace(stig.ReleaseInfo); release != "" {
+		title = fmt.Sprintf("%s (%s)", title, release)
+	}
+	return title
 }
 
-// buildControlImplementationSets builds control implementation sets from STIG rules
-func buildControlImplementationSets(checklist *cklb.Checklist, cciControlMap map[string]string) []oscalTypes.ControlImplementationSet {
-    implementationUUID := uuid.New().String()
+// buildCapability builds a capability incorporating the components of every STIG in the checklist
+func buildCapability(title string, checklist *cklb.Checklist, components []oscalTypes.DefinedComponent, opts generateOptions) oscalTypes.Capability {
+	incorporates := make([]oscalTypes.IncorporatesComponent, 0, len(components))
+	for _, definedComponent := range components {
+		incorporates = append(incorporates, oscalTypes.IncorporatesComponent{
+			ComponentUuid: definedComponent.UUID,
+			Description: definedComponent.Title,
+		})
+	}
+	
+	return oscalTypes.Capability{
+		UUID: opts.uuids.New("capability"),
+		Name: title,
+		Description: fmt.Sprintf("The STIGs of Checklist: %s", checklist.Data.Title),
+		IncorporatesComponents: &incorporates,
+	}
+}
+
+// controlRequirement collects the rules that implement one control of the control implementation
+type controlRequirement struct {
+    controlId string
+    rules     []ruleContribution
+    // sourceControls are the 800-53 controls a crosswalk target is reached from
+    sourceControls []string
+    // enhancements are the 800-53 enhancements rolled up under a base control
+    enhancements []string
+}
+
+// ruleContribution is a rule and the CCIs through which it implements a control
+type ruleContribution struct {
+    rule cklb.STIGRule
+    stig *cklb.STIG
+    ccis []string
+    // control is the 800-53 control the CCIs map to, before any rollup or crosswalk
+    control string
+    // reason explains why a rule under a catch-all control has no control mapping
+    reason string
+    // withdrawn are the withdrawn controls the CCIs map to, which control was incorporated into
+    withdrawn []string
+}
+
+// buildControlImplementationSets builds control implementation sets from STIG rules,
+// with one implemented requirement per control listing every rule that contributes to it.
+// It also returns the rules that map to no control.
+func buildControlImplementationSets(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions) ([]oscalTypes.ControlImplementationSet, []unmappedRule) {
+    implementationUUID := opts.uuids.New("control-implementation")
     
     implementationSet := oscalTypes.ControlImplementationSet{
         UUID: implementationUUID,
-        Source: "https://raw.githubusercontent.com/usnistgov/oscal-content/main/nist.gov/SP800-53/rev5/json/NIST_SP-800-53_rev5_catalog.json", 
+        Source: catalogSources[opts.revision],
         Description: fmt.Sprintf("Control implementation for %s", checklist.Data.Title),
         ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{},
     }
 
-    // Process each STIG rule
-    for _, stig := range checklist.Data.STIGs {
-        for _, rule := range stig.Rules {
-            reqUUID := uuid.New().String()
-            
-            // Extract CCIs from the rule
-            cciNumbers := extractCCINumbers(rule)
-            
-            // Determine control ID from CCIs if available
-            controlId := "unknown"
-            if len(cciNumbers) > 0 && cciControlMap != nil {
-                // Try to find a control ID for any of the CCIs
-                for _, cci := range cciNumbers {
-                    if control, exists := cciControlMap[cci]; exists && control != "" {
-                        controlId = control
-                        break
-                    }
-                }
-            }
-            
-            // Add all CCIs to remarks
-            remarks := rule.RuleID
-            if len(cciNumbers) > 0 {
-                remarks += " - CCIs: " + fmt.Sprintf("%v", cciNumbers)
+    if opts.profile != nil {
+        implementationSet.Source = opts.profile.Source
+    }
+    if opts.crosswalk != nil {
+        implementationSet.Source = opts.crosswalk.Source
+        implementationSet.Description = fmt.Sprintf("%s control implementation for %s", opts.crosswalk.Framework, checklist.Data.Title)
+    }
+
+    // Order requirements by control id so output does not depend on rule order
+    requirements, unmapped := aggregateRequirements(checklist, cciIndex, opts)
+    sort.SliceStable(requirements, func(i, j int) bool {
+        return common.LessID(requirements[i].controlId, requirements[j].controlId)
+    })
+    for _, controlReq := range requirements {
+        requirement := buildRequirement(controlReq, cciIndex, opts)
+        if !inBaseline(controlReq, opts) {
+            if opts.outOfBaseline == OutOfBaselineOmit {
+                continue
             }
-            
-            // This matches the ImplementedRequirementControlImplementation struct definition
-            requirement := oscalTypes.ImplementedRequirementControlImplementation{
-                UUID:        reqUUID,
-                ControlId:   controlId,
-                Description: rule.RuleTitle,
-                Remarks:     remarks,
+            props := []oscalTypes.Property{outOfBaselineProp(opts)}
+            if requirement.Props != nil {
+                props = append(*requirement.Props, props...)
             }
-            
-            implementationSet.ImplementedRequirements = append(
-                implementationSet.ImplementedRequirements, 
-                requirement,
-            )
+            requirement.Props = &props
         }
+        implementationSet.ImplementedRequirements = append(implementationSet.ImplementedRequirements, requirement)
     }
 
-    return []oscalTypes.ControlImplementationSet{implementationSet}
+    return []oscalTypes.ControlImplementationSet{implementationSet}, unmapped
+}
+
+// aggregateRequirements groups the rules of a checklist by the controls their CCIs map
+// to, in the order the controls are first reached. A rule with CCIs mapping to several
+// controls contributes to each of them. Rules that reach no control are returned as
+// unmapped, and also grouped under the catch-all control of the UnmappedControl policy.
+func aggregateRequirements(checklist *cklb.Checklist, cciIndex *cciparsing.CCIIndex, opts generateOptions) ([]*controlRequirement, []unmappedRule) {
+	var requirements []*controlRequirement
+	var unmapped []unmappedRule
+	byControl := make(map[string]*controlRequirement)
+	
+	add := func(controlId string, contribution ruleContribution) *controlRequirement {
+		controlReq, exists := byControl[controlId]
+		if !exists {
+			controlReq = &controlRequirement{controlId: controlId}
+			byControl[controlId] = controlReq
+			requirements = append(requirements, controlReq)
+		}
+		// A rule reaching the same control through several 800-53 controls is listed once
+		if last := len(controlReq.rules) - 1; last >= 0 && controlReq.rules[last].rule.RuleID == contribution.rule.RuleID {
+			controlReq.rules[last].ccis = appendUnique(controlReq.rules[last].ccis, contribution.ccis...)
+			controlReq.rules[last].withdrawn = appendUnique(controlReq.rules[last].withdrawn, contribution.withdrawn...)
+			return controlReq
+		}
+		controlReq.rules = append(controlReq.rules, contribution)
+		return controlReq
+	}
+	
+	for i := range checklist.Data.STIGs {
+		stig := &checklist.Data.STIGs[i]
+		for _, rule := range stig.Rules {
+			cciNumbers := extractCCINumbers(rule)
+			
+			// Group the rule's CCIs by the 800-53 control they map to
+			var controls []string
+			ccisByControl := make(map[string][]string)
+			if cciIndex != nil {
+				for _, cci := range cciNumbers {
+					control := cciIndex.ControlID(cci)
+					if control == "" {
+						continue
+					}
+					if _, exists := ccisByControl[control]; !exists {
+						controls = append(controls, control)
+					}
+					ccisByControl[control] = append(ccisByControl[control], cci)
+				}
+			}
+			reason := ""
+			if len(controls) == 0 {
+				reason = unmappedReason(cciNumbers, cciIndex)
+			}
+			
+			mapped := false
+			for _, mappedControl := range controls {
+				// Withdrawn controls stand for the controls they were incorporated into
+				for _, control := range resolveControl(mappedControl, opts.catalog) {
+					contribution := ruleContribution{rule: rule, stig: stig, ccis: ccisByControl[mappedControl], control: control}
+					if control != mappedControl {
+						contribution.withdrawn = []string{mappedControl}
+					}
+					
+					// Key the requirement to the target framework of a crosswalk instead
+					if opts.crosswalk != nil {
+						for _, target := range crosswalkTargets(control, opts.crosswalk) {
+							mapped = true
+							controlReq := add(target, contribution)
+							controlReq.sourceControls = appendUnique(controlReq.sourceControls, control)
+						}
+						continue
+					}
+					
+					if opts.rollupEnhancements {
+						if base := baseControl(control); base != control {
+							mapped = true
+							controlReq := add(base, contribution)
+							controlReq.enhancements = appendUnique(controlReq.enhancements, control)
+							continue
+						}
+					}
+					mapped = true
+					add(control, contribution)
+				}
+			}
+			if mapped {
+				continue
+			}
+			
+			if reason == "" {
+				reason = reasonNoCrosswalk
+			}
+			if opts.unmappedPolicy == UnmappedControl {
+				add(opts.unmappedControl, ruleContribution{rule: rule, stig: stig, ccis: cciNumbers, reason: reason})
+			}
+			unmapped = append(unmapped, unmappedRule{stig: stig.STIGName, rule: rule, reason: reason})
+		}
+	}
+	
+	return requirements, unmapped
+}
+
+// buildRequirement builds the implemented requirement of a control from its contributing rules
+func buildRequirement(controlReq *controlRequirement, cciIndex *cciparsing.CCIIndex, opts generateOptions) oscalTypes.ImplementedRequirementControlImplementation {
+	var remarks []string
+	var ccis []string
+	var statuses []string
+	props := []oscalTypes.Property{}
+	for _, contribution := range controlReq.rules {
+		remark := contribution.rule.RuleID + " - CCIs: " + strings.Join(contribution.ccis, ", ")
+		if contribution.reason != "" {
+			remark += " (no control mapping: " + contribution.reason + ")"
+		}
+		remarks = append(remarks, remark)
+		ccis = appendUnique(ccis, contribution.ccis...)
+		props = append(props, ruleProps(contribution.rule, contribution.stig)...)
+		props = append(props, statusProps(contribution.rule, opts.statusMapping)...)
+		statuses = append(statuses, implementationStatus(contribution.rule.Status, opts.statusMapping))
+	}
+	
+	// The control's implementation status rolls up those of its rules
+	props = append([]oscalTypes.Property{{Name: "implementation-status", Value: rollupStatus(statuses), Ns: common.Namespace}}, props...)
+	
+	// Quote the CCI definitions and every NIST revision they reference
+	if cciIndex != nil {
+		for _, cci := range ccis {
+			if item, exists := cciIndex.Get(cci); exists {
+				remarks = append(remarks, describeCCI(item))
+			}
+		}
+	}
+	
+	requirement := oscalTypes.ImplementedRequirementControlImplementation{
+		UUID:        opts.uuids.New("implemented-requirement", controlReq.controlId),
+		ControlId:   controlReq.controlId,
+		Description: describeRules(controlReq.rules),
+		Remarks:     strings.Join(remarks, "\n"),
+	}
+	if links := benchmarkLinks(controlReq.rules, opts.benchmarks); len(links) > 0 {
+		requirement.Links = &links
+	}
+	
+	for _, control := range controlReq.sourceControls {
+		props = append(props, oscalTypes.Property{Name: "nist-800-53-control", Value: control, Ns: common.Namespace})
+	}
+	for _, enhancement := range controlReq.enhancements {
+		props = append(props, oscalTypes.Property{Name: "control-enhancement", Value: enhancement, Ns: common.Namespace})
+	}
+	var withdrawn []string
+	for _, contribution := range controlReq.rules {
+		withdrawn = appendUnique(withdrawn, contribution.withdrawn...)
+	}
+	for _, control := range withdrawn {
+		props = append(props, oscalTypes.Property{Name: "withdrawn-control", Value: control, Ns: common.Namespace, Remarks: "Withdrawn from the catalog and incorporated into this control"})
+	}
+	
+	// Statements and assessment objectives belong to the 800-53 catalog. Objectives are
+	// only given for rules whose CCIs map to the requirement's control itself.
+	if opts.crosswalk == nil {
+		var direct []ruleContribution
+		for _, contribution := range controlReq.rules {
+			if contribution.control == controlReq.controlId {
+				direct = append(direct, contribution)
+			}
+		}
+		
+		// Point the requirement at the statement parts its CCIs name, with the fix and check text of its rules
+		if statements := buildStatements(controlReq.rules, controlReq.controlId, cciIndex, opts.catalog, opts.uuids); len(statements) > 0 {
+			requirement.Statements = &statements
+		}
+		
+		// Cite the 800-53A assessment objectives the rules exercise
+		for _, objectiveID := range assessmentObjectives(direct, cciIndex, opts.catalog) {
+			props = append(props, oscalTypes.Property{Name: "assessment-objective", Value: objectiveID, Ns: common.Namespace})
+		}
+	}
+	
+	if len(props) > 0 {
+		requirement.Props = &props
+	}
+	return requirement
+}
+
+// crosswalkTargets returns the controls an 800-53 control maps to in the crosswalk,
+// skipping target ids that are not valid OSCAL tokens
+func crosswalkTargets(controlId string, cw *crosswalk.Crosswalk) []string {
+	var targets []string
+	for _, target := range cw.Targets(controlId) {
+		if !cciparsing.IsValidOSCALToken(target) {
+			fmt.Printf("Warning: skipping %s control id %q, it is not a valid OSCAL token\n", cw.Framework, target)
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// baseControl returns the base control of a control enhancement, such as ac-2 for ac-2.3
+func baseControl(controlId string) string {
+	if i := strings.Index(controlId, "."); i > 0 {
+		return controlId[:i]
+	}
+	return controlId
+}
+
+// describeRules describes the rules implementing a control: the rule title of a single
+// rule, otherwise a list of the rules and their titles
+func describeRules(contributions []ruleContribution) string {
+	if len(contributions) == 1 {
+		return contributions[0].rule.RuleTitle
+	}
+	
+	lines := make([]string, 0, len(contributions))
+	for _, contribution := range contributions {
+		lines = append(lines, fmt.Sprintf("- %s: %s", contribution.rule.RuleID, contribution.rule.RuleTitle))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildStatements builds one statement per control part named by the CCIs of the
+// contributing rules that map to controlId itself. Other rules, such as rules whose
+// CCIs name no part or rolled up enhancements, are given under the control's top-level
+// statement. With a catalog, statement ids are checked against it and fall back to
+// their closest existing parent part. A statement's description holds the fix text of
+// its rules and its remarks hold their check procedures.
+func buildStatements(contributions []ruleContribution, controlId string, cciIndex *cciparsing.CCIIndex, controlCatalog *catalog.Catalog, uuids *common.UUIDGenerator) []oscalTypes.ControlStatementImplementation {
+	if cciIndex == nil {
+		return nil
+	}
+	
+	resolve := func(statementID string) string {
+		if statementID != "" && controlCatalog != nil {
+			return controlCatalog.ResolveStatement(statementID)
+		}
+		return statementID
+	}
+	
+	var statementIDs []string
+	byStatement := make(map[string][]ruleContribution)
+	addRule := func(statementID string, contribution ruleContribution, ccis ...string) {
+		rules := byStatement[statementID]
+		if len(rules) == 0 {
+			statementIDs = append(statementIDs, statementID)
+		}
+		if last := len(rules) - 1; last >= 0 && rules[last].rule.RuleID == contribution.rule.RuleID {
+			rules[last].ccis = appendUnique(rules[last].ccis, ccis...)
+		} else {
+			rules = append(rules, ruleContribution{rule: contribution.rule, stig: contribution.stig, ccis: ccis, control: contribution.control})
+		}
+		byStatement[statementID] = rules
+	}
+	
+	for _, contribution := range contributions {
+		placed := false
+		if contribution.control == controlId {
+			for _, cci := range contribution.ccis {
+				if statementID := resolve(cciIndex.StatementID(cci)); statementID != "" {
+					addRule(statementID, contribution, cci)
+					placed = true
+				}
+			}
+		}
+		if !placed {
+			if statementID := resolve(controlId + "_smt"); statementID != "" {
+				addRule(statementID, contribution, contribution.ccis...)
+			}
+		}
+	}
+	
+	sort.SliceStable(statementIDs, func(i, j int) bool {
+		return common.LessID(statementIDs[i], statementIDs[j])
+	})
+	
+	var statements []oscalTypes.ControlStatementImplementation
+	for _, statementID := range statementIDs {
+		var descriptions, remarks []string
+		for _, contribution := range byStatement[statementID] {
+			descriptions = append(descriptions, fixDescription(contribution.rule))
+			remarks = append(remarks, checkRemarks(contribution.rule, contribution.ccis))
+		}
+		statements = append(statements, oscalTypes.ControlStatementImplementation{
+			StatementId: statementID,
+			UUID:        uuids.New("statement", statementID),
+			Description: strings.Join(descriptions, "\n\n"),
+			Remarks:     strings.Join(remarks, "\n\n"),
+		})
+	}
+	
+	return statements
+}
+
+// assessmentObjectives returns the assessment objective ids of the CCIs of the
+// contributing rules. With a catalog, objective ids are checked against it and
+// refined to the determination statement named by the CCI's 800-53A reference.
+func assessmentObjectives(contributions []ruleContribution, cciIndex *cciparsing.CCIIndex, controlCatalog *catalog.Catalog) []string {
+	if cciIndex == nil {
+		return nil
+	}
+	
+	var objectives []string
+	for _, contribution := range contributions {
+		for _, cci := range contribution.ccis {
+			objectiveID := cciIndex.ObjectiveID(cci)
+			if objectiveID != "" && controlCatalog != nil {
+				item, _ := cciIndex.Get(cci)
+				objectiveID = controlCatalog.ResolveObjective(objectiveID, item.AssessmentDetermination())
+			}
+			if objectiveID != "" {
+				objectives = appendUnique(objectives, objectiveID)
+			}
+		}
+	}
+	
+	return objectives
+}
+
+// appendUnique appends the values that are not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// describeCCI formats a CCI definition with the NIST references it maps to
+func describeCCI(item cciparsing.CCIItem) string {
+	description := fmt.Sprintf("%s: %s", item.ID, strings.TrimSpace(item.Definition))
+	
+	var references []string
+	for _, ref := range item.NISTReferences() {
+		references = append(references, fmt.Sprintf("%s: %s", ref.Title, ref.Index))
+	}
+	if len(references) > 0 {
+		description += " (" + strings.Join(references, "; ") + ")"
+	}
+	
+	return description
 }
 
 // writeComponent writes the OSCAL component to a JSON file
@@ -0,0 +1,580 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/catalog"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/profile"
+)
+
+const testCCIListXML = `<?xml version="1.0" encoding="utf-8"?>
+<cci_list xmlns="http://iase.disa.mil/cci">
+  <metadata>
+    <version>2025-09-19</version>
+    <publishdate>2025-09-19</publishdate>
+  </metadata>
+  <cci_items>
+    <cci_item id="CCI-000015">
+      <status>draft</status>
+      <definition>Support the management of system accounts using organization-defined automated mechanisms.</definition>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" index="AC-2 (1)" />
+      </references>
+    </cci_item>
+    <cci_item id="CCI-000016">
+      <status>draft</status>
+      <definition>Automatically remove or disable temporary accounts.</definition>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" index="AC-2 (2)" />
+      </references>
+    </cci_item>
+    <cci_item id="CCI-000366">
+      <status>draft</status>
+      <definition>Implement the security configuration settings.</definition>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" index="CM-6 b" />
+      </references>
+    </cci_item>
+    <cci_item id="CCI-001762">
+      <status>draft</status>
+      <definition>Disable organization-defined functions, ports, protocols, and services within the information system deemed to be unnecessary and/or nonsecure.</definition>
+      <references>
+        <reference creator="NIST" title="NIST SP 800-53 Revision 5" version="5" index="CM-7 b" />
+      </references>
+    </cci_item>
+  </cci_items>
+</cci_list>`
+
+func testChecklist() *cklb.Checklist {
+	return &cklb.Checklist{Data: cklb.ChecklistFile{
+		Title: "Test Checklist",
+		STIGs: []cklb.STIG{{
+			STIGName: "Test STIG",
+			Rules: []cklb.STIGRule{
+				{RuleID: "SV-1r1_rule", RuleTitle: "Rule one", CCIs: []string{"CCI-000366"}, FixText: "Set PermitRootLogin to no.", CheckContent: "Verify <sshd_config> sets PermitRootLogin no."},
+				{RuleID: "SV-2r1_rule", RuleTitle: "Rule two", CCIs: []string{"CCI-000366", "CCI-001762"}},
+				{RuleID: "SV-3r1_rule", RuleTitle: "Rule three", CCIs: []string{"CCI-000015"}},
+				{RuleID: "SV-4r1_rule", RuleTitle: "Rule four", CCIs: []string{"CCI-000016"}},
+				{RuleID: "SV-5r1_rule", RuleTitle: "Rule five", CCIs: []string{"CCI-999999"}},
+				{RuleID: "SV-6r1_rule", RuleTitle: "Rule six"},
+			},
+		}},
+	}}
+}
+
+func testCCIIndex(t *testing.T) *cciparsing.CCIIndex {
+	t.Helper()
+	index, err := cciparsing.ParseCCIIndexReader(strings.NewReader(testCCIListXML))
+	if err != nil {
+		t.Fatalf("ParseCCIIndexReader() returned error: %v", err)
+	}
+	return index
+}
+
+// requirementsByControl indexes requirements by control id, failing on duplicates
+func requirementsByControl(t *testing.T, sets []oscalTypes.ControlImplementationSet) map[string]oscalTypes.ImplementedRequirementControlImplementation {
+	t.Helper()
+	requirements := make(map[string]oscalTypes.ImplementedRequirementControlImplementation)
+	for _, set := range sets {
+		for _, requirement := range set.ImplementedRequirements {
+			if _, exists := requirements[requirement.ControlId]; exists {
+				t.Errorf("control %s has more than one implemented requirement", requirement.ControlId)
+			}
+			requirements[requirement.ControlId] = requirement
+		}
+	}
+	return requirements
+}
+
+func propValues(requirement oscalTypes.ImplementedRequirementControlImplementation, name string) []string {
+	var values []string
+	if requirement.Props != nil {
+		for _, prop := range *requirement.Props {
+			if prop.Name == name {
+				values = append(values, prop.Value)
+			}
+		}
+	}
+	return values
+}
+
+func TestBuildControlImplementationSets(t *testing.T) {
+	sets, unmapped := buildControlImplementationSets(testChecklist(), testCCIIndex(t), generateOptions{revision: "5", unmappedPolicy: UnmappedOmit})
+	requirements := requirementsByControl(t, sets)
+
+	testCases := []struct {
+		controlID string
+		rules     []string
+	}{
+		{"cm-6", []string{"SV-1r1_rule", "SV-2r1_rule"}},
+		{"cm-7", []string{"SV-2r1_rule"}},
+		{"ac-2.1", []string{"SV-3r1_rule"}},
+		{"ac-2.2", []string{"SV-4r1_rule"}},
+	}
+	if len(requirements) != len(testCases) {
+		t.Errorf("got %d implemented requirements, expected %d", len(requirements), len(testCases))
+	}
+
+	for _, tc := range testCases {
+		requirement, exists := requirements[tc.controlID]
+		if !exists {
+			t.Errorf("no implemented requirement for %s", tc.controlID)
+			continue
+		}
+		if rules := propValues(requirement, "stig-rule"); strings.Join(rules, ",") != strings.Join(tc.rules, ",") {
+			t.Errorf("%s stig-rule props = %v, expected %v", tc.controlID, rules, tc.rules)
+		}
+	}
+
+	// Implementation statuses are not values of the core OSCAL prop, so they are namespaced
+	for controlID, requirement := range requirements {
+		for _, prop := range *requirement.Props {
+			if prop.Name == "implementation-status" && prop.Ns != common.Namespace {
+				t.Errorf("%s implementation-status prop %q is in namespace %q, expected %s", controlID, prop.Value, prop.Ns, common.Namespace)
+			}
+		}
+	}
+
+	if len(unmapped) != 2 || unmapped[0].reason != reasonUnknownCCIs || unmapped[1].reason != reasonNoCCIs {
+		t.Errorf("unmapped = %+v, expected SV-5r1_rule and SV-6r1_rule", unmapped)
+	}
+
+	cm6 := requirements["cm-6"]
+	if cm6.Statements == nil || len(*cm6.Statements) != 1 || (*cm6.Statements)[0].StatementId != "cm-6_smt.b" {
+		t.Fatalf("cm-6 statements = %v, expected one cm-6_smt.b statement", cm6.Statements)
+	}
+	if remarks := (*cm6.Statements)[0].Remarks; !strings.Contains(remarks, "SV-1r1_rule") || !strings.Contains(remarks, "SV-2r1_rule") {
+		t.Errorf("cm-6_smt.b remarks = %q, expected both rules", remarks)
+	}
+	if description := (*cm6.Statements)[0].Description; !strings.Contains(description, "### SV-1r1_rule: Rule one\n\nSet PermitRootLogin to no.") {
+		t.Errorf("cm-6_smt.b description = %q, expected the fix text of SV-1r1_rule", description)
+	}
+	if remarks := (*cm6.Statements)[0].Remarks; !strings.Contains(remarks, `Verify \<sshd\_config\> sets PermitRootLogin no.`) {
+		t.Errorf("cm-6_smt.b remarks = %q, expected the escaped check content of SV-1r1_rule", remarks)
+	}
+
+	// Rules whose CCIs name no statement part are given under the control's statement
+	ac21 := requirements["ac-2.1"]
+	if ac21.Statements == nil || (*ac21.Statements)[0].StatementId != "ac-2.1_smt" {
+		t.Errorf("ac-2.1 statements = %v, expected one ac-2.1_smt statement", ac21.Statements)
+	}
+}
+
+func TestBuildControlImplementationSetsRollup(t *testing.T) {
+	sets, _ := buildControlImplementationSets(testChecklist(), testCCIIndex(t), generateOptions{revision: "5", rollupEnhancements: true})
+	requirements := requirementsByControl(t, sets)
+
+	if _, exists := requirements["ac-2.1"]; exists {
+		t.Error("ac-2.1 was not rolled up under ac-2")
+	}
+	ac2, exists := requirements["ac-2"]
+	if !exists {
+		t.Fatal("no implemented requirement for ac-2")
+	}
+	if enhancements := propValues(ac2, "control-enhancement"); strings.Join(enhancements, ",") != "ac-2.1,ac-2.2" {
+		t.Errorf("ac-2 control-enhancement props = %v, expected [ac-2.1 ac-2.2]", enhancements)
+	}
+}
+
+func TestUnmappedControlPolicy(t *testing.T) {
+	opts := generateOptions{revision: "5", unmappedPolicy: UnmappedControl, unmappedControl: "pm-1"}
+	if err := validateUnmappedPolicy(opts.unmappedPolicy, opts.unmappedControl); err != nil {
+		t.Fatalf("validateUnmappedPolicy() returned error: %v", err)
+	}
+	if err := validateUnmappedPolicy(UnmappedControl, ""); err == nil {
+		t.Error("validateUnmappedPolicy() without a catch-all control returned no error")
+	}
+	if err := validateUnmappedPolicy("unknown", ""); err == nil {
+		t.Error("validateUnmappedPolicy(unknown) returned no error")
+	}
+
+	sets, unmapped := buildControlImplementationSets(testChecklist(), testCCIIndex(t), opts)
+	requirements := requirementsByControl(t, sets)
+
+	if _, exists := requirements["unknown"]; exists {
+		t.Error("rules without a control mapping were listed under unknown")
+	}
+	if rules := propValues(requirements["pm-1"], "stig-rule"); strings.Join(rules, ",") != "SV-5r1_rule,SV-6r1_rule" {
+		t.Errorf("pm-1 stig-rule props = %v, expected [SV-5r1_rule SV-6r1_rule]", rules)
+	}
+	if len(unmapped) != 2 {
+		t.Errorf("got %d unmapped rules, expected 2 for the summary", len(unmapped))
+	}
+}
+
+func TestReproducibleComponent(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	generate := func() []byte {
+		checklist := testChecklist()
+		opts := generateOptions{revision: "5", unmappedPolicy: UnmappedOmit}
+		if err := setReproducibility(&opts, checklist, true); err != nil {
+			t.Fatalf("setReproducibility() returned error: %v", err)
+		}
+		component, _, err := createComponent(checklist, testCCIIndex(t), opts, "checklist.cklb")
+		if err != nil {
+			t.Fatalf("createComponent() returned error: %v", err)
+		}
+		data, err := json.Marshal(component)
+		if err != nil {
+			t.Fatalf("json.Marshal() returned error: %v", err)
+		}
+		return data
+	}
+
+	first, second := generate(), generate()
+	if !bytes.Equal(first, second) {
+		t.Error("reproducible component definitions of the same checklist differ")
+	}
+	if !bytes.Contains(first, []byte(`"last-modified":"2023-11-14T22:13:20Z"`)) {
+		t.Error("last-modified time does not follow SOURCE_DATE_EPOCH")
+	}
+
+	// Adding a STIG to the checklist keeps the UUIDs of the components of the others
+	uuidsOf := func(checklist *cklb.Checklist) []string {
+		opts := generateOptions{revision: "5", unmappedPolicy: UnmappedOmit}
+		if err := setReproducibility(&opts, checklist, true); err != nil {
+			t.Fatalf("setReproducibility() returned error: %v", err)
+		}
+		component, _, err := createComponent(checklist, testCCIIndex(t), opts, "checklist.cklb")
+		if err != nil {
+			t.Fatalf("createComponent() returned error: %v", err)
+		}
+		definedComponent := (*component.Components)[0]
+		uuids := []string{definedComponent.UUID}
+		for _, requirement := range (*definedComponent.ControlImplementations)[0].ImplementedRequirements {
+			uuids = append(uuids, requirement.UUID)
+		}
+		return uuids
+	}
+	extended := testChecklist()
+	extended.Data.STIGs = append(extended.Data.STIGs, cklb.STIG{STIGName: "Other STIG", STIGID: "Other_STIG", UUID: "0d8b9d3c-7c0e-4f4e-9d55-6b1c2c3d4e5f"})
+	if before, after := uuidsOf(testChecklist()), uuidsOf(extended); !reflect.DeepEqual(before, after) {
+		t.Errorf("UUIDs after adding a STIG = %v, expected %v", after, before)
+	}
+
+	// Requirements are ordered by control id, not by rule order
+	var controlIDs []string
+	sets, _ := buildControlImplementationSets(testChecklist(), testCCIIndex(t), generateOptions{revision: "5"})
+	for _, requirement := range sets[0].ImplementedRequirements {
+		controlIDs = append(controlIDs, requirement.ControlId)
+	}
+	if got := strings.Join(controlIDs, ","); got != "ac-2.1,ac-2.2,cm-6,cm-7" {
+		t.Errorf("control ids = %s, expected ac-2.1,ac-2.2,cm-6,cm-7", got)
+	}
+}
+
+func TestRuleProps(t *testing.T) {
+	rule := cklb.STIGRule{
+		RuleID:      "SV-1r1_rule",
+		GroupID:     "V-1",
+		RuleVersion: "TEST-00-000010",
+		SRGID:       "SRG-OS-000001-GPOS-00001",
+		Severity:    "high",
+		Weight:      "10.0",
+		CCIs:        []string{"CCI-000015", "CCI-000016"},
+		LegacyIDs:   []string{"V-100", "SV-100"},
+		Overrides:   json.RawMessage(`{"severity": {"severity": "medium", "reason": "Mitigated by network isolation"}}`),
+	}
+	stig := &cklb.STIG{STIGName: "Test STIG", STIGID: "Test_STIG", ReleaseInfo: "Release: 1 Benchmark Date: 01 Jan 2025"}
+
+	values := make(map[string][]string)
+	for _, prop := range ruleProps(rule, stig) {
+		if prop.Group != "SV-1r1_rule" {
+			t.Errorf("prop %s has group %q, expected the rule id", prop.Name, prop.Group)
+		}
+		values[prop.Name] = append(values[prop.Name], prop.Value)
+		if prop.Name == "severity" && !strings.Contains(prop.Remarks, "Mitigated by network isolation") {
+			t.Errorf("severity remarks = %q, expected the override reason", prop.Remarks)
+		}
+	}
+
+	expected := map[string]string{
+		"stig-rule":    "SV-1r1_rule",
+		"group-id":     "V-1",
+		"rule-version": "TEST-00-000010",
+		"srg-id":       "SRG-OS-000001-GPOS-00001",
+		"severity":     "medium",
+		"weight":       "10.0",
+		"cci":          "CCI-000015,CCI-000016",
+		"legacy-id":    "V-100,SV-100",
+		"stig-id":      "Test_STIG",
+		"stig-release": "Release: 1 Benchmark Date: 01 Jan 2025",
+	}
+	for name, value := range expected {
+		if got := strings.Join(values[name], ","); got != value {
+			t.Errorf("%s props = %s, expected %s", name, got, value)
+		}
+	}
+}
+
+func TestBenchmarkLinks(t *testing.T) {
+	checklist := testChecklist()
+	resources, benchmarks := benchmarkResources(checklist, nil)
+	if len(resources) != 1 || resources[0].Title != "Test STIG" {
+		t.Fatalf("benchmarkResources() = %v, expected one resource for Test STIG", resources)
+	}
+
+	sets, _ := buildControlImplementationSets(checklist, testCCIIndex(t), generateOptions{revision: "5", benchmarks: benchmarks})
+	requirement := requirementsByControl(t, sets)["cm-6"]
+	if requirement.Links == nil || len(*requirement.Links) != 1 || (*requirement.Links)[0].Href != "#"+resources[0].UUID {
+		t.Errorf("cm-6 links = %v, expected a link to the benchmark resource", requirement.Links)
+	}
+}
+
+func TestRollupStatus(t *testing.T) {
+	testCases := []struct {
+		statuses []string
+		expected string
+	}{
+		{[]string{StatusImplemented, StatusImplemented}, StatusImplemented},
+		{[]string{StatusImplemented, StatusNotApplicable}, StatusImplemented},
+		{[]string{StatusNotApplicable, StatusNotApplicable}, StatusNotApplicable},
+		{[]string{StatusImplemented, StatusNotImplemented}, StatusPartial},
+		{[]string{StatusImplemented, StatusPlanned}, StatusPartial},
+		{[]string{StatusPlanned, StatusNotImplemented}, StatusNotImplemented},
+		{[]string{StatusPlanned}, StatusPlanned},
+	}
+
+	for _, tc := range testCases {
+		if result := rollupStatus(tc.statuses); result != tc.expected {
+			t.Errorf("rollupStatus(%v) = %s, expected %s", tc.statuses, result, tc.expected)
+		}
+	}
+}
+
+func TestStatusMapping(t *testing.T) {
+	mapping, err := statusMapping(map[string]string{"open": "Partial"})
+	if err != nil {
+		t.Fatalf("statusMapping() returned error: %v", err)
+	}
+	if mapping["open"] != StatusPartial || mapping["not_a_finding"] != StatusImplemented {
+		t.Errorf("statusMapping() = %v, expected open=partial with the other defaults", mapping)
+	}
+	if _, err := statusMapping(map[string]string{"open": "broken"}); err == nil {
+		t.Error("statusMapping() with an unknown implementation status returned no error")
+	}
+	if _, err := statusMapping(map[string]string{"failed": "partial"}); err == nil {
+		t.Error("statusMapping() with an unknown checklist status returned no error")
+	}
+
+	rule := cklb.STIGRule{RuleID: "SV-1r1_rule", Status: "open", FindingDetails: "PermitRootLogin yes", Comments: "Fix scheduled."}
+	props := statusProps(rule, mapping)
+	if len(props) != 2 || props[0].Value != "open" || props[1].Value != StatusPartial {
+		t.Fatalf("statusProps() = %v, expected checklist-status open and implementation-status partial", props)
+	}
+	if !strings.Contains(props[1].Remarks, "PermitRootLogin yes") || !strings.Contains(props[1].Remarks, "Fix scheduled.") {
+		t.Errorf("implementation-status remarks = %q, expected finding details and comments", props[1].Remarks)
+	}
+}
+
+func TestComponentPerSTIG(t *testing.T) {
+	checklist := testChecklist()
+	checklist.Data.STIGs[0].DisplayName = "Test"
+	checklist.Data.STIGs[0].ReleaseInfo = "Release: 1 Benchmark Date: 01 Jan 2025"
+	checklist.Data.STIGs = append(checklist.Data.STIGs, cklb.STIG{
+		STIGName:    "Second STIG",
+		DisplayName: "Second",
+		STIGID:      "Second_STIG",
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-7r1_rule", RuleTitle: "Rule seven", CCIs: []string{"CCI-000366"}},
+		},
+	})
+
+	opts := generateOptions{revision: "5", capability: true, uuids: common.NewNameBasedUUIDGenerator("checklist")}
+	component, unmapped, err := createComponent(checklist, testCCIIndex(t), opts, "checklist.cklb")
+	if err != nil {
+		t.Fatalf("createComponent() returned error: %v", err)
+	}
+	if len(unmapped) != 2 {
+		t.Errorf("got %d unmapped rules, expected 2", len(unmapped))
+	}
+
+	components := *component.Components
+	if len(components) != 2 {
+		t.Fatalf("got %d components, expected one per STIG", len(components))
+	}
+	if components[0].Title != "Test (Release: 1 Benchmark Date: 01 Jan 2025)" || components[1].Title != "Second" {
+		t.Errorf("component titles = %q, %q", components[0].Title, components[1].Title)
+	}
+
+	// Each component implements the controls of its own STIG
+	first := requirementsByControl(t, *components[0].ControlImplementations)
+	second := requirementsByControl(t, *components[1].ControlImplementations)
+	if len(first) != 4 || len(second) != 1 {
+		t.Errorf("got %d and %d requirements, expected 4 and 1", len(first), len(second))
+	}
+	if first["cm-6"].UUID == second["cm-6"].UUID {
+		t.Error("the cm-6 requirements of both components have the same UUID")
+	}
+
+	if component.Capabilities == nil || len(*component.Capabilities) != 1 {
+		t.Fatal("expected one capability")
+	}
+	capability := (*component.Capabilities)[0]
+	if capability.Name != "Test Checklist" || len(*capability.IncorporatesComponents) != 2 ||
+		(*capability.IncorporatesComponents)[1].ComponentUuid != components[1].UUID {
+		t.Errorf("capability = %+v, expected it to incorporate both components", capability)
+	}
+}
+
+func TestComponentMetadata(t *testing.T) {
+	config := metadataConfig{
+		Type:      "Hardware",
+		Purpose:   "Firewall appliance",
+		Protocols: []protocolConfig{{Name: "https", PortRanges: []portRangeConfig{{Start: 443, Transport: "tcp"}}}},
+		Roles:     []roleConfig{{ID: "provider", Title: "Provider"}},
+		Parties: []partyConfig{
+			{Type: "organization", Name: "ACME Corp", ShortName: "acme"},
+			{Type: "person", Name: "Jo", UUID: "5E0D4A0C-6F1E-4D8A-9D5B-6E6B1B2A3C4D"},
+		},
+		ResponsibleRoles: []responsibleRoleConfig{{RoleID: "provider", PartyUUIDs: []string{"acme", "5E0D4A0C-6F1E-4D8A-9D5B-6E6B1B2A3C4D"}}},
+	}
+	metadata, err := newComponentMetadata(config, common.NewNameBasedUUIDGenerator("test"))
+	if err != nil {
+		t.Fatalf("newComponentMetadata() returned error: %v", err)
+	}
+	if metadata.componentType != "hardware" || metadata.version != defaultDocumentVersion {
+		t.Errorf("newComponentMetadata() type %q and version %q, expected hardware and %s", metadata.componentType, metadata.version, defaultDocumentVersion)
+	}
+	portRanges := *metadata.protocols[0].PortRanges
+	if portRanges[0].End != 443 || portRanges[0].Transport != "TCP" {
+		t.Errorf("port range = %+v, expected 443-443 over TCP", portRanges[0])
+	}
+	partyUUIDs := metadata.responsibleRoles[0].PartyUuids
+	if partyUUIDs == nil || len(*partyUUIDs) != 2 || (*partyUUIDs)[0] != metadata.parties[0].UUID || (*partyUUIDs)[1] != "5e0d4a0c-6f1e-4d8a-9d5b-6e6b1b2a3c4d" {
+		t.Errorf("responsible role parties = %v, expected the UUIDs of party acme and Jo", partyUUIDs)
+	}
+
+	opts := generateOptions{revision: "5", fallback: true, metadata: metadata}
+	definition, _, err := createComponent(testChecklist(), testCCIIndex(t), opts, "test.cklb")
+	if err != nil {
+		t.Fatalf("createComponent() returned error: %v", err)
+	}
+	definedComponent := (*definition.Components)[0]
+	if definedComponent.Type != "hardware" || definedComponent.Purpose != "Firewall appliance" || definedComponent.ResponsibleRoles == nil || definedComponent.Protocols == nil {
+		t.Errorf("component = %+v, expected the configured type, purpose, protocols and responsible roles", definedComponent)
+	}
+	if definition.Metadata.Parties == nil || definition.Metadata.Roles == nil {
+		t.Error("metadata has no parties or roles, expected the configured ones")
+	}
+
+	invalid := []metadataConfig{
+		{Type: "widget"},
+		{Protocols: []protocolConfig{{Name: "https", PortRanges: []portRangeConfig{{Start: 443, Transport: "SCTP"}}}}},
+		{Protocols: []protocolConfig{{Name: "https", PortRanges: []portRangeConfig{{Start: 443, End: 70000, Transport: "TCP"}}}}},
+		{Parties: []partyConfig{{Type: "team", Name: "ACME"}}},
+		{Parties: []partyConfig{{Type: "person", Name: "Jo", UUID: "not-a-uuid"}}},
+		{Parties: []partyConfig{{Type: "person", Name: "Jo", LocationUUIDs: []string{"5e0d4a0c-6f1e-4d8a-9d5b-6e6b1b2a3c4d"}}}},
+		{Roles: []roleConfig{{ID: "1provider", Title: "Provider"}}},
+		{ResponsibleRoles: []responsibleRoleConfig{{RoleID: "provider"}}},
+		{Roles: []roleConfig{{ID: "provider", Title: "Provider"}}, ResponsibleRoles: []responsibleRoleConfig{{RoleID: "provider", PartyUUIDs: []string{"nobody"}}}},
+	}
+	for _, config := range invalid {
+		if _, err := newComponentMetadata(config, nil); err == nil {
+			t.Errorf("newComponentMetadata(%+v) returned no error", config)
+		}
+	}
+}
+
+func TestProfileBaseline(t *testing.T) {
+	withIDs := []string{"ac-2.1", "ac-3", "cm-6"}
+	baseline := profile.New(&oscalTypes.Profile{
+		Metadata: oscalTypes.Metadata{Title: "Test Baseline"},
+		Imports:  []oscalTypes.Import{{IncludeControls: &[]oscalTypes.SelectControlById{{WithIds: &withIDs}}}},
+	})
+	baseline.Source = "baseline_profile.json"
+	opts := generateOptions{revision: "5", fallback: true, profile: baseline, outOfBaseline: OutOfBaselineTag}
+
+	sets, _ := buildControlImplementationSets(testChecklist(), testCCIIndex(t), opts)
+	if sets[0].Source != "baseline_profile.json" {
+		t.Errorf("Source = %s, expected the profile", sets[0].Source)
+	}
+	requirements := requirementsByControl(t, sets)
+	for control, expected := range map[string]bool{"ac-2.1": false, "ac-2.2": true, "cm-6": false, "cm-7": true} {
+		if tagged := len(propValues(requirements[control], "out-of-baseline")) > 0; tagged != expected {
+			t.Errorf("%s tagged out-of-baseline = %t, expected %t", control, tagged, expected)
+		}
+	}
+
+	opts.outOfBaseline = OutOfBaselineOmit
+	sets, _ = buildControlImplementationSets(testChecklist(), testCCIIndex(t), opts)
+	requirements = requirementsByControl(t, sets)
+	_, hasAC21 := requirements["ac-2.1"]
+	_, hasCM6 := requirements["cm-6"]
+	if len(requirements) != 2 || !hasAC21 || !hasCM6 {
+		t.Errorf("got %d requirements with out-of-baseline omit, expected only ac-2.1 and cm-6", len(requirements))
+	}
+
+	uncovered, outside := baselineCoverage(testChecklist(), testCCIIndex(t), opts)
+	if strings.Join(uncovered, ",") != "ac-3" || strings.Join(outside, ",") != "ac-2.2,cm-7" {
+		t.Errorf("baselineCoverage() = %v, %v, expected [ac-3] and [ac-2.2 cm-7]", uncovered, outside)
+	}
+}
+
+func TestCatalogControlIDs(t *testing.T) {
+	withdrawn := []oscalTypes.Property{{Name: "status", Value: "withdrawn"}}
+	incorporated := []oscalTypes.Link{{Href: "#ac-2", Rel: "incorporated-into"}}
+	controlCatalog := catalog.New(&oscalTypes.Catalog{Controls: &[]oscalTypes.Control{
+		{ID: "ac-2", Parts: &[]oscalTypes.Part{{ID: "ac-2_smt", Name: "statement"}}, Controls: &[]oscalTypes.Control{
+			{ID: "ac-2.1", Props: &withdrawn, Links: &incorporated},
+			{ID: "ac-2.2"},
+		}},
+		{ID: "cm-6", Parts: &[]oscalTypes.Part{{ID: "cm-6_smt", Name: "statement"}}},
+	}})
+	opts := generateOptions{revision: "5", fallback: true, catalog: controlCatalog}
+
+	sets, _ := buildControlImplementationSets(testChecklist(), testCCIIndex(t), opts)
+	requirements := requirementsByControl(t, sets)
+	if _, exists := requirements["ac-2.1"]; exists {
+		t.Error("withdrawn control ac-2.1 has an implemented requirement")
+	}
+	if values := propValues(requirements["ac-2"], "withdrawn-control"); strings.Join(values, ",") != "ac-2.1" {
+		t.Errorf("ac-2 withdrawn-control props = %v, expected [ac-2.1]", values)
+	}
+	if rules := propValues(requirements["ac-2"], "stig-rule"); strings.Join(rules, ",") != "SV-3r1_rule" {
+		t.Errorf("ac-2 stig-rule props = %v, expected [SV-3r1_rule]", rules)
+	}
+
+	var findings []string
+	for _, finding := range checkControlIDs(testChecklist(), testCCIIndex(t), opts) {
+		findings = append(findings, finding.kind+" "+finding.id+"->"+strings.Join(finding.resolved, ","))
+	}
+	expected := "control ac-2.1->ac-2;control cm-7->;statement cm-6_smt.b->cm-6_smt"
+	if strings.Join(findings, ";") != expected {
+		t.Errorf("checkControlIDs() = %v, expected %s", findings, expected)
+	}
+}
+
+func TestEmbeddedCatalogControlIDs(t *testing.T) {
+	controlCatalog, err := loadCatalog("5")
+	if err != nil {
+		t.Fatalf("loadCatalog() returned error: %v", err)
+	}
+	if controlCatalog == nil {
+		t.Skip("no NIST SP 800-53 revision 5 catalog is embedded, run go generate ./internal/oscal/catalog")
+	}
+
+	// ac-2.10 is withdrawn in revision 5 and incorporated into ac-2, ac-2.13 is current
+	cciIndex := testCCIIndex(t)
+	cciIndex.SetOverrides(map[string]string{"CCI-000015": "ac-2.10", "CCI-000016": "ac-2.13", "CCI-001762": "ac-2.99"})
+	opts := generateOptions{revision: "5", fallback: true, catalog: controlCatalog}
+
+	var findings []string
+	for _, finding := range checkControlIDs(testChecklist(), cciIndex, opts) {
+		findings = append(findings, finding.kind+" "+finding.id+"->"+strings.Join(finding.resolved, ","))
+	}
+	expected := "control ac-2.10->ac-2;control ac-2.99->"
+	if strings.Join(findings, ";") != expected {
+		t.Errorf("checkControlIDs() = %v, expected %s", findings, expected)
+	}
+}
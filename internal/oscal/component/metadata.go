@@ -0,0 +1,413 @@
+package component
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+)
+
+// Defaults for components and documents without configured metadata
+const (
+	defaultComponentType   = "software"
+	defaultDocumentVersion = "1.0.0"
+)
+
+// ComponentTypes are the OSCAL allowed values of a defined component's type
+var ComponentTypes = []string{
+	"interconnection",
+	"software",
+	"hardware",
+	"service",
+	"policy",
+	"physical",
+	"process-procedure",
+	"plan",
+	"guidance",
+	"standard",
+	"validation",
+}
+
+// partyTypes are the OSCAL allowed values of a party's type
+var partyTypes = []string{"person", "organization"}
+
+// transports are the OSCAL allowed values of a port range's transport
+var transports = []string{"TCP", "UDP"}
+
+// tokenPattern matches OSCAL tokens, which role ids and protocol names must be
+var tokenPattern = regexp.MustCompile(`^(\p{L}|_)(\p{L}|\p{N}|[.\-_])*$`)
+
+// countryPattern matches the ISO 3166-1 alpha-2 country codes of addresses
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// metadataConfig is the component metadata as written in a metadata file or the
+// oscal.component config section
+type metadataConfig struct {
+	Type             string                  `mapstructure:"type"`
+	Purpose          string                  `mapstructure:"purpose"`
+	Version          string                  `mapstructure:"version"`
+	Protocols        []protocolConfig        `mapstructure:"protocols"`
+	ResponsibleRoles []responsibleRoleConfig `mapstructure:"responsibleRoles"`
+	Roles            []roleConfig            `mapstructure:"roles"`
+	Parties          []partyConfig           `mapstructure:"parties"`
+	Locations        []locationConfig        `mapstructure:"locations"`
+}
+
+type protocolConfig struct {
+	Name       string            `mapstructure:"name"`
+	Title      string            `mapstructure:"title"`
+	PortRanges []portRangeConfig `mapstructure:"portRanges"`
+}
+
+type portRangeConfig struct {
+	Start     int    `mapstructure:"start"`
+	End       int    `mapstructure:"end"`
+	Transport string `mapstructure:"transport"`
+}
+
+// responsibleRoleConfig assigns a role to parties, referenced by UUID or short name
+type responsibleRoleConfig struct {
+	RoleID     string   `mapstructure:"roleId"`
+	PartyUUIDs []string `mapstructure:"partyUuids"`
+}
+
+type roleConfig struct {
+	ID          string `mapstructure:"id"`
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+}
+
+type partyConfig struct {
+	UUID           string   `mapstructure:"uuid"`
+	Type           string   `mapstructure:"type"`
+	Name           string   `mapstructure:"name"`
+	ShortName      string   `mapstructure:"shortName"`
+	EmailAddresses []string `mapstructure:"emailAddresses"`
+	LocationUUIDs  []string `mapstructure:"locationUuids"`
+}
+
+type locationConfig struct {
+	UUID    string        `mapstructure:"uuid"`
+	Title   string        `mapstructure:"title"`
+	Address addressConfig `mapstructure:"address"`
+	URLs    []string      `mapstructure:"urls"`
+}
+
+type addressConfig struct {
+	AddrLines  []string `mapstructure:"addrLines"`
+	City       string   `mapstructure:"city"`
+	State      string   `mapstructure:"state"`
+	PostalCode string   `mapstructure:"postalCode"`
+	Country    string   `mapstructure:"country"`
+}
+
+// componentMetadata is the validated component metadata: what each component is and
+// who is responsible for it
+type componentMetadata struct {
+	componentType    string
+	purpose          string
+	version          string
+	protocols        []oscalTypes.Protocol
+	responsibleRoles []oscalTypes.ResponsibleRole
+	roles            []oscalTypes.Role
+	parties          []oscalTypes.Party
+	locations        []oscalTypes.Location
+}
+
+// loadComponentMetadata reads the component metadata from the file named by
+// oscal.component.metadataFile, if any, then applies the settings of the
+// oscal.component config section and flags over it and validates the result
+func loadComponentMetadata(uuids *common.UUIDGenerator) (componentMetadata, error) {
+	var config metadataConfig
+	if path := viper.GetString("oscal.component.metadataFile"); path != "" {
+		fileConfig := viper.New()
+		fileConfig.SetConfigFile(path)
+		if err := fileConfig.ReadInConfig(); err != nil {
+			return componentMetadata{}, fmt.Errorf("failed to read metadata file %s: %w", path, err)
+		}
+		if err := fileConfig.Unmarshal(&config); err != nil {
+			return componentMetadata{}, fmt.Errorf("failed to parse metadata file %s: %w", path, err)
+		}
+	}
+
+	for key, value := range map[string]*string{
+		"oscal.component.type":    &config.Type,
+		"oscal.component.purpose": &config.Purpose,
+		"oscal.component.version": &config.Version,
+	} {
+		if setting := viper.GetString(key); setting != "" {
+			*value = setting
+		}
+	}
+	for key, value := range map[string]interface{}{
+		"oscal.component.protocols":        &config.Protocols,
+		"oscal.component.responsibleRoles": &config.ResponsibleRoles,
+		"oscal.component.roles":            &config.Roles,
+		"oscal.component.parties":          &config.Parties,
+		"oscal.component.locations":        &config.Locations,
+	} {
+		if !viper.IsSet(key) {
+			continue
+		}
+		if err := viper.UnmarshalKey(key, value); err != nil {
+			return componentMetadata{}, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+	}
+
+	return newComponentMetadata(config, uuids)
+}
+
+// newComponentMetadata validates component metadata against the OSCAL allowed values
+// and the references between roles, parties and locations. Parties and locations
+// without a UUID get one from uuids.
+func newComponentMetadata(config metadataConfig, uuids *common.UUIDGenerator) (componentMetadata, error) {
+	metadata := componentMetadata{
+		componentType: strings.ToLower(strings.TrimSpace(config.Type)),
+		purpose:       strings.TrimSpace(config.Purpose),
+		version:       strings.TrimSpace(config.Version),
+	}
+	if metadata.componentType == "" {
+		metadata.componentType = defaultComponentType
+	}
+	if !contains(ComponentTypes, metadata.componentType) {
+		return componentMetadata{}, fmt.Errorf("invalid component type %q, expected one of %s", config.Type, strings.Join(ComponentTypes, ", "))
+	}
+	if metadata.version == "" {
+		metadata.version = defaultDocumentVersion
+	}
+
+	for _, protocol := range config.Protocols {
+		built, err := buildProtocol(protocol)
+		if err != nil {
+			return componentMetadata{}, err
+		}
+		metadata.protocols = append(metadata.protocols, built)
+	}
+
+	locationUUIDs := make(map[string]bool)
+	for _, location := range config.Locations {
+		built, err := buildLocation(location, uuids)
+		if err != nil {
+			return componentMetadata{}, err
+		}
+		if locationUUIDs[built.UUID] {
+			return componentMetadata{}, fmt.Errorf("duplicate location uuid %s", built.UUID)
+		}
+		locationUUIDs[built.UUID] = true
+		metadata.locations = append(metadata.locations, built)
+	}
+
+	// Parties are referenced by UUID or by short name
+	partyUUIDs := make(map[string]string)
+	for _, party := range config.Parties {
+		built, err := buildParty(party, locationUUIDs, uuids)
+		if err != nil {
+			return componentMetadata{}, err
+		}
+		if _, ok := partyUUIDs[built.UUID]; ok {
+			return componentMetadata{}, fmt.Errorf("duplicate party uuid %s", built.UUID)
+		}
+		partyUUIDs[built.UUID] = built.UUID
+		if built.ShortName != "" {
+			if _, ok := partyUUIDs[built.ShortName]; ok {
+				return componentMetadata{}, fmt.Errorf("duplicate party short name %s", built.ShortName)
+			}
+			partyUUIDs[built.ShortName] = built.UUID
+		}
+		metadata.parties = append(metadata.parties, built)
+	}
+
+	roleIDs := make(map[string]bool)
+	for _, role := range config.Roles {
+		role.ID = strings.TrimSpace(role.ID)
+		if !tokenPattern.MatchString(role.ID) {
+			return componentMetadata{}, fmt.Errorf("invalid role id %q, role ids must be OSCAL tokens", role.ID)
+		}
+		if roleIDs[role.ID] {
+			return componentMetadata{}, fmt.Errorf("duplicate role id %s", role.ID)
+		}
+		if strings.TrimSpace(role.Title) == "" {
+			return componentMetadata{}, fmt.Errorf("role %s has no title", role.ID)
+		}
+		roleIDs[role.ID] = true
+		metadata.roles = append(metadata.roles, oscalTypes.Role{
+			ID:          role.ID,
+			Title:       strings.TrimSpace(role.Title),
+			Description: strings.TrimSpace(role.Description),
+		})
+	}
+
+	for _, responsibleRole := range config.ResponsibleRoles {
+		roleID := strings.TrimSpace(responsibleRole.RoleID)
+		if !roleIDs[roleID] {
+			return componentMetadata{}, fmt.Errorf("responsible role %q is not a defined role", responsibleRole.RoleID)
+		}
+		built := oscalTypes.ResponsibleRole{RoleId: roleID}
+		var parties []string
+		for _, reference := range responsibleRole.PartyUUIDs {
+			// Short names are matched as given, UUIDs are stored lowercased
+			partyUUID, ok := partyUUIDs[strings.TrimSpace(reference)]
+			if !ok {
+				partyUUID, ok = partyUUIDs[strings.ToLower(strings.TrimSpace(reference))]
+			}
+			if !ok {
+				return componentMetadata{}, fmt.Errorf("responsible role %s references unknown party %q", roleID, reference)
+			}
+			parties = appendUnique(parties, partyUUID)
+		}
+		if len(parties) > 0 {
+			built.PartyUuids = &parties
+		}
+		metadata.responsibleRoles = append(metadata.responsibleRoles, built)
+	}
+
+	return metadata, nil
+}
+
+// buildProtocol validates a protocol and its port ranges
+func buildProtocol(config protocolConfig) (oscalTypes.Protocol, error) {
+	name := strings.TrimSpace(config.Name)
+	if !tokenPattern.MatchString(name) {
+		return oscalTypes.Protocol{}, fmt.Errorf("invalid protocol name %q, protocol names must be OSCAL tokens", config.Name)
+	}
+
+	protocol := oscalTypes.Protocol{Name: name, Title: strings.TrimSpace(config.Title)}
+	var portRanges []oscalTypes.PortRange
+	for _, portRange := range config.PortRanges {
+		transport := strings.ToUpper(strings.TrimSpace(portRange.Transport))
+		if !contains(transports, transport) {
+			return oscalTypes.Protocol{}, fmt.Errorf("invalid transport %q for protocol %s, expected one of %s", portRange.Transport, name, strings.Join(transports, ", "))
+		}
+		end := portRange.End
+		if end == 0 {
+			end = portRange.Start
+		}
+		if portRange.Start < 0 || end > 65535 || portRange.Start > end {
+			return oscalTypes.Protocol{}, fmt.Errorf("invalid port range %d-%d for protocol %s", portRange.Start, end, name)
+		}
+		portRanges = append(portRanges, oscalTypes.PortRange{Start: portRange.Start, End: end, Transport: transport})
+	}
+	if len(portRanges) > 0 {
+		protocol.PortRanges = &portRanges
+	}
+	return protocol, nil
+}
+
+// buildLocation validates a location, giving it a UUID if it has none
+func buildLocation(config locationConfig, uuids *common.UUIDGenerator) (oscalTypes.Location, error) {
+	title := strings.TrimSpace(config.Title)
+	locationUUID, err := configuredUUID(config.UUID, "location", uuids, "location", title)
+	if err != nil {
+		return oscalTypes.Location{}, err
+	}
+
+	location := oscalTypes.Location{UUID: locationUUID, Title: title}
+	address := oscalTypes.Address{
+		City:       strings.TrimSpace(config.Address.City),
+		State:      strings.TrimSpace(config.Address.State),
+		PostalCode: strings.TrimSpace(config.Address.PostalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(config.Address.Country)),
+	}
+	if address.Country != "" && !countryPattern.MatchString(address.Country) {
+		return oscalTypes.Location{}, fmt.Errorf("invalid country %q for location %s, expected an ISO 3166-1 alpha-2 code", config.Address.Country, locationUUID)
+	}
+	if len(config.Address.AddrLines) > 0 {
+		lines := append([]string(nil), config.Address.AddrLines...)
+		address.AddrLines = &lines
+	}
+	if address != (oscalTypes.Address{}) {
+		location.Address = &address
+	}
+	if len(config.URLs) > 0 {
+		urls := append([]string(nil), config.URLs...)
+		location.Urls = &urls
+	}
+	if location.Title == "" && location.Address == nil && location.Urls == nil {
+		return oscalTypes.Location{}, fmt.Errorf("location %s needs a title, address or URL", locationUUID)
+	}
+	return location, nil
+}
+
+// buildParty validates a party and its locations, giving it a UUID if it has none
+func buildParty(config partyConfig, locationUUIDs map[string]bool, uuids *common.UUIDGenerator) (oscalTypes.Party, error) {
+	name := strings.TrimSpace(config.Name)
+	partyType := strings.ToLower(strings.TrimSpace(config.Type))
+	if !contains(partyTypes, partyType) {
+		return oscalTypes.Party{}, fmt.Errorf("invalid type %q for party %s, expected one of %s", config.Type, name, strings.Join(partyTypes, ", "))
+	}
+	if name == "" && config.ShortName == "" {
+		return oscalTypes.Party{}, fmt.Errorf("party needs a name or short name")
+	}
+	partyUUID, err := configuredUUID(config.UUID, "party", uuids, "party", name, config.ShortName)
+	if err != nil {
+		return oscalTypes.Party{}, err
+	}
+
+	party := oscalTypes.Party{
+		UUID:      partyUUID,
+		Type:      partyType,
+		Name:      name,
+		ShortName: strings.TrimSpace(config.ShortName),
+	}
+	if len(config.EmailAddresses) > 0 {
+		for _, email := range config.EmailAddresses {
+			if !strings.Contains(email, "@") {
+				return oscalTypes.Party{}, fmt.Errorf("invalid email address %q for party %s", email, name)
+			}
+		}
+		emails := append([]string(nil), config.EmailAddresses...)
+		party.EmailAddresses = &emails
+	}
+	if len(config.LocationUUIDs) > 0 {
+		var locations []string
+		for _, locationUUID := range config.LocationUUIDs {
+			locationUUID = strings.ToLower(strings.TrimSpace(locationUUID))
+			if !locationUUIDs[locationUUID] {
+				return oscalTypes.Party{}, fmt.Errorf("party %s references unknown location %s", name, locationUUID)
+			}
+			locations = append(locations, locationUUID)
+		}
+		party.LocationUuids = &locations
+	}
+	return party, nil
+}
+
+// configuredUUID checks a configured UUID, or issues one from names if none is configured
+func configuredUUID(value, kind string, uuids *common.UUIDGenerator, names ...string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return uuids.New(names...), nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil || (parsed.Version() != 4 && parsed.Version() != 5) {
+		return "", fmt.Errorf("invalid %s uuid %q, expected a version 4 or 5 UUID", kind, value)
+	}
+	return strings.ToLower(value), nil
+}
+
+// componentProtocols copies the configured protocols for a component, with UUIDs
+// issued within the component's scope
+func componentProtocols(protocols []oscalTypes.Protocol, uuids *common.UUIDGenerator) []oscalTypes.Protocol {
+	copies := make([]oscalTypes.Protocol, 0, len(protocols))
+	for _, protocol := range protocols {
+		protocol.UUID = uuids.New("protocol", protocol.Name)
+		copies = append(copies, protocol)
+	}
+	return copies
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+	"github.com/open-automation-construct/oscalctl/internal/srg"
+)
+
+// ruleProps returns namespaced props describing a STIG rule and the STIG it belongs
+// to. The props of each rule share the rule id as their group, since an implemented
+// requirement lists the props of all its contributing rules.
+func ruleProps(rule cklb.STIGRule, stig *cklb.STIG) []oscalTypes.Property {
+	group := ruleGroup(rule)
+
+	var props []oscalTypes.Property
+	add := func(name, value, remarks string) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return
+		}
+		props = append(props, oscalTypes.Property{Name: name, Value: value, Group: group, Remarks: remarks, Ns: common.Namespace})
+	}
+
+	add("stig-rule", rule.RuleID, "")
+	add("group-id", rule.GroupID, "")
+	add("rule-version", rule.RuleVersion, "")
+	for _, srgID := range srg.RuleSRGIDs(rule) {
+		add("srg-id", srgID, "")
+	}
+
+	severityRemarks := ""
+	if override, ok := rule.Override("severity"); ok && override.Value != rule.Severity {
+		severityRemarks = fmt.Sprintf("Overridden from %s", rule.Severity)
+		if override.Reason != "" {
+			severityRemarks += ": " + override.Reason
+		}
+	}
+	add("severity", rule.EffectiveSeverity(), severityRemarks)
+	add("weight", rule.Weight, "")
+
+	for _, cci := range rule.CCIs {
+		add("cci", cci, "")
+	}
+	for _, legacyID := range rule.LegacyIDs {
+		add("legacy-id", legacyID, "")
+	}
+
+	if stig != nil {
+		add("stig-id", stig.STIGID, "")
+		add("stig-release", stig.ReleaseInfo, "")
+	}
+
+	return props
+}
+
+// fixDescription describes how a rule is implemented: its fix text, or its title if
+// it has none, under a heading naming the rule
+func fixDescription(rule cklb.STIGRule) string {
+	heading := "### " + rule.RuleID
+	if title := strings.TrimSpace(rule.RuleTitle); title != "" {
+		heading += ": " + common.EscapeMarkdown(title)
+	}
+
+	body := common.MarkdownParagraphs(rule.FixText)
+	if body == "" {
+		return heading
+	}
+	return heading + "\n\n" + body
+}
+
+// checkRemarks gives the check procedure of a rule and the CCIs it verifies
+func checkRemarks(rule cklb.STIGRule, ccis []string) string {
+	remarks := "### " + rule.RuleID + " check"
+	if len(ccis) > 0 {
+		remarks += "\n\nCCIs: " + strings.Join(ccis, ", ")
+	}
+	if body := common.MarkdownParagraphs(rule.CheckContent); body != "" {
+		remarks += "\n\n" + body
+	}
+	return remarks
+}
+
+// ruleGroup returns the prop group of a rule's props: its rule id, if that is a valid token
+func ruleGroup(rule cklb.STIGRule) string {
+	if cciparsing.IsValidOSCALToken(rule.RuleID) {
+		return rule.RuleID
+	}
+	return ""
+}
+
+// stigKey identifies a STIG of the checklist
+func stigKey(stig *cklb.STIG) string {
+	if stig.STIGID != "" {
+		return stig.STIGID
+	}
+	return stig.STIGName
+}
+
+// stigProps returns namespaced props with the id, version and release of a STIG
+func stigProps(stig *cklb.STIG) []oscalTypes.Property {
+	var props []oscalTypes.Property
+	for _, prop := range []struct{ name, value string }{
+		{"stig-id", stig.STIGID},
+		{"stig-version", stig.Version},
+		{"stig-release", stig.ReleaseInfo},
+	} {
+		if prop.value != "" {
+			props = append(props, oscalTypes.Property{Name: prop.name, Value: prop.value, Ns: common.Namespace})
+		}
+	}
+	return props
+}
+
+// benchmarkResources returns a back-matter resource for the benchmark of each STIG in
+// the checklist, and the resource UUIDs by STIG key for requirements to link to
+func benchmarkResources(checklist *cklb.Checklist, uuids *common.UUIDGenerator) ([]oscalTypes.Resource, map[string]string) {
+	var resources []oscalTypes.Resource
+	benchmarks := make(map[string]string)
+
+	for i := range checklist.Data.STIGs {
+		stig := &checklist.Data.STIGs[i]
+		key := stigKey(stig)
+		if key == "" || benchmarks[key] != "" {
+			continue
+		}
+
+		title := stig.STIGName
+		if title == "" {
+			title = stig.DisplayName
+		}
+		resource := oscalTypes.Resource{
+			UUID:        uuids.New("resource", "benchmark", key),
+			Title:       title,
+			Description: stig.ReleaseInfo,
+		}
+
+		if props := stigProps(stig); len(props) > 0 {
+			resource.Props = &props
+		}
+
+		// Rules name the benchmark document their check content comes from
+		for _, rule := range stig.Rules {
+			if rule.CheckContentRef != nil && rule.CheckContentRef.Href != "" {
+				resource.Rlinks = &[]oscalTypes.ResourceLink{{Href: rule.CheckContentRef.Href, MediaType: "application/xml"}}
+				break
+			}
+		}
+
+		benchmarks[key] = resource.UUID
+		resources = append(resources, resource)
+	}
+
+	return resources, benchmarks
+}
+
+// benchmarkLinks links a requirement to the benchmarks of the STIGs its rules come from
+func benchmarkLinks(contributions []ruleContribution, benchmarks map[string]string) []oscalTypes.Link {
+	var links []oscalTypes.Link
+	seen := make(map[string]bool)
+	for _, contribution := range contributions {
+		if contribution.stig == nil {
+			continue
+		}
+		key := stigKey(contribution.stig)
+		resourceUUID, ok := benchmarks[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, oscalTypes.Link{Href: "#" + resourceUUID, Rel: "reference", Text: contribution.stig.STIGName})
+	}
+	return links
+}
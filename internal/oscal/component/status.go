@@ -0,0 +1,148 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+)
+
+// Implementation statuses rules and controls are mapped to
+const (
+	StatusImplemented    = "implemented"
+	StatusPartial        = "partial"
+	StatusPlanned        = "planned"
+	StatusAlternative    = "alternative"
+	StatusNotApplicable  = "not-applicable"
+	StatusNotImplemented = "not-implemented"
+)
+
+// defaultStatusMapping maps CKLB rule statuses to implementation statuses
+var defaultStatusMapping = map[string]string{
+	"not_a_finding":  StatusImplemented,
+	"open":           StatusNotImplemented,
+	"not_applicable": StatusNotApplicable,
+	"not_reviewed":   StatusPlanned,
+}
+
+// statusRank orders implementation statuses from best to worst for the control rollup
+var statusRank = map[string]int{
+	StatusNotApplicable:  0,
+	StatusImplemented:    1,
+	StatusAlternative:    2,
+	StatusPlanned:        3,
+	StatusPartial:        4,
+	StatusNotImplemented: 5,
+}
+
+// statusMapping returns the default status mapping with the given entries, keyed by
+// CKLB status, replacing the defaults
+func statusMapping(overrides map[string]string) (map[string]string, error) {
+	mapping := make(map[string]string, len(defaultStatusMapping))
+	for status, implementation := range defaultStatusMapping {
+		mapping[status] = implementation
+	}
+
+	for status, implementation := range overrides {
+		status = strings.ToLower(strings.TrimSpace(status))
+		implementation = strings.ToLower(strings.TrimSpace(implementation))
+		if _, ok := defaultStatusMapping[status]; !ok {
+			return nil, fmt.Errorf("unknown checklist status %q in status mapping, expected one of %s", status, strings.Join(sortedKeys(defaultStatusMapping), ", "))
+		}
+		if _, ok := statusRank[implementation]; !ok {
+			return nil, fmt.Errorf("unknown implementation status %q for %s, expected one of %s", implementation, status, strings.Join(sortedKeys(statusRank), ", "))
+		}
+		mapping[status] = implementation
+	}
+	return mapping, nil
+}
+
+// implementationStatus maps a CKLB rule status to an implementation status. Rules
+// without a status are treated as not reviewed.
+func implementationStatus(status string, mapping map[string]string) string {
+	if status == "" {
+		status = "not_reviewed"
+	}
+	if implementation, ok := mapping[status]; ok {
+		return implementation
+	}
+	if implementation, ok := defaultStatusMapping[status]; ok {
+		return implementation
+	}
+	return StatusPlanned
+}
+
+// statusProps returns the checklist status of a rule and the implementation status it
+// maps to, grouped by the rule id like its other props. The finding details and
+// comments of the rule are given as remarks of the implementation status.
+func statusProps(rule cklb.STIGRule, mapping map[string]string) []oscalTypes.Property {
+	group := ruleGroup(rule)
+
+	var remarks []string
+	if details := strings.TrimSpace(rule.FindingDetails); details != "" {
+		remarks = append(remarks, "Finding details: "+details)
+	}
+	if comments := strings.TrimSpace(rule.Comments); comments != "" {
+		remarks = append(remarks, "Comments: "+comments)
+	}
+
+	status := rule.Status
+	if status == "" {
+		status = "not_reviewed"
+	}
+	return []oscalTypes.Property{
+		{Name: "checklist-status", Value: status, Group: group, Ns: common.Namespace},
+		{
+			Name:    "implementation-status",
+			Value:   implementationStatus(rule.Status, mapping),
+			Group:   group,
+			Remarks: strings.Join(remarks, "\n\n"),
+			Ns:      common.Namespace,
+		},
+	}
+}
+
+// rollupStatus works out the implementation status of a control from those of its
+// rules. Not applicable rules are ignored unless all rules are not applicable. If the
+// remaining rules agree, their status is the control's. A control with both implemented
+// rules and rules that are not is partial, otherwise the worst status wins.
+func rollupStatus(statuses []string) string {
+	var applicable []string
+	for _, status := range statuses {
+		if status != StatusNotApplicable {
+			applicable = append(applicable, status)
+		}
+	}
+	if len(applicable) == 0 {
+		return StatusNotApplicable
+	}
+
+	worst, met, unmet := applicable[0], false, false
+	for _, status := range applicable {
+		if statusRank[status] > statusRank[worst] {
+			worst = status
+		}
+		if status == StatusImplemented || status == StatusAlternative {
+			met = true
+		} else {
+			unmet = true
+		}
+	}
+	if met && unmet {
+		return StatusPartial
+	}
+	return worst
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package component
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-automation-construct/oscalctl/internal/cciparsing"
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// Policies for STIG rules none of whose CCIs map to a control
+const (
+	// UnmappedOmit leaves unmapped rules out of the component definition
+	UnmappedOmit = "omit"
+	// UnmappedReport leaves them out and writes them to a CSV report
+	UnmappedReport = "report"
+	// UnmappedControl lists them under a catch-all control
+	UnmappedControl = "control"
+	// UnmappedFail fails generation if any rule is unmapped
+	UnmappedFail = "fail"
+)
+
+// UnmappedPolicies lists the supported unmapped rule policies
+var UnmappedPolicies = []string{UnmappedOmit, UnmappedReport, UnmappedControl, UnmappedFail}
+
+// Reasons a rule has no control mapping
+const (
+	reasonNoCCIs      = "rule cites no CCIs"
+	reasonUnknownCCIs = "CCIs are not in the CCI list"
+	reasonNoReference = "CCIs have no reference to the selected NIST SP 800-53 revision"
+	reasonNoCrosswalk = "controls have no crosswalk entry"
+)
+
+// unmappedRule is a rule that could not be mapped to any control, and why
+type unmappedRule struct {
+	stig   string
+	rule   cklb.STIGRule
+	reason string
+}
+
+// validateUnmappedPolicy checks the policy and, for UnmappedControl, the catch-all control id
+func validateUnmappedPolicy(policy, control string) error {
+	switch policy {
+	case UnmappedOmit, UnmappedReport, UnmappedFail:
+		return nil
+	case UnmappedControl:
+		if control == "" {
+			return fmt.Errorf("the %s unmapped rule policy needs a catch-all control, use --unmapped-control", UnmappedControl)
+		}
+		if !cciparsing.IsValidOSCALToken(control) {
+			return fmt.Errorf("catch-all control %q is not a valid OSCAL control id", control)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown unmapped rule policy %q, expected one of %s", policy, strings.Join(UnmappedPolicies, ", "))
+	}
+}
+
+// unmappedReason explains why none of the CCIs of a rule map to a control
+func unmappedReason(cciNumbers []string, cciIndex *cciparsing.CCIIndex) string {
+	if len(cciNumbers) == 0 {
+		return reasonNoCCIs
+	}
+	if cciIndex != nil {
+		for _, cci := range cciNumbers {
+			if _, exists := cciIndex.Get(cci); exists {
+				return reasonNoReference
+			}
+		}
+	}
+	return reasonUnknownCCIs
+}
+
+// unmappedReportPath returns the report path to use, deriving one from the
+// component output path if none is configured
+func unmappedReportPath(reportPath, outputPath string) string {
+	if reportPath != "" {
+		return reportPath
+	}
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-unmapped.csv"
+}
+
+// writeUnmappedReport writes one CSV row per unmapped rule
+func writeUnmappedReport(unmapped []unmappedRule, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	w := csv.NewWriter(file)
+	if err := w.Write([]string{"stig", "group_id", "rule_id", "rule_version", "rule_title", "ccis", "reason"}); err != nil {
+		return err
+	}
+	for _, u := range unmapped {
+		row := []string{
+			u.stig, u.rule.GroupID, u.rule.RuleID, u.rule.RuleVersion, u.rule.RuleTitle,
+			strings.Join(u.rule.CCIs, ";"), u.reason,
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// summarizeUnmappedRules prints how many rules were unmapped and why
+func summarizeUnmappedRules(unmapped []unmappedRule, opts generateOptions) {
+	if len(unmapped) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, u := range unmapped {
+		counts[u.reason]++
+	}
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	policy := opts.unmappedPolicy
+	if policy == UnmappedControl {
+		policy += " " + opts.unmappedControl
+	}
+	fmt.Printf("%d rules have no control mapping (policy: %s):\n", len(unmapped), policy)
+	for _, reason := range reasons {
+		fmt.Printf("  - %d: %s\n", counts[reason], reason)
+	}
+}
+
+// handleUnmappedRules applies the unmapped rule policy once the component is built:
+// it writes the report or fails generation. Rules under a catch-all control were
+// already placed by buildControlImplementationSets.
+func handleUnmappedRules(unmapped []unmappedRule, opts generateOptions, outputPath string) error {
+	if len(unmapped) == 0 {
+		return nil
+	}
+
+	switch opts.unmappedPolicy {
+	case UnmappedFail:
+		summarizeUnmappedRules(unmapped, opts)
+		return fmt.Errorf("%d rules have no control mapping and the unmapped rule policy is %s", len(unmapped), UnmappedFail)
+	case UnmappedReport:
+		path := unmappedReportPath(opts.unmappedReport, outputPath)
+		if err := writeUnmappedReport(unmapped, path); err != nil {
+			return fmt.Errorf("failed to write unmapped rule report: %w", err)
+		}
+		fmt.Printf("Wrote %d unmapped rules to %s\n", len(unmapped), path)
+	}
+	return nil
+}
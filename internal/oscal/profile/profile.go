@@ -0,0 +1,166 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// Profile indexes the controls an OSCAL profile, such as an 800-53 baseline, selects
+// from its imports. Imported profiles are not resolved, only the selections made by
+// the profile itself.
+type Profile struct {
+	Profile *oscalTypes.Profile
+	// Source identifies the profile, such as the path it was loaded from
+	Source string
+	// controls lists the ids selected with with-ids, in profile order
+	controls []string
+	included map[string]bool
+	excluded map[string]bool
+	// withChildren holds the ids whose child controls are included as well
+	withChildren map[string]bool
+	patterns     []string
+	includeAll   bool
+}
+
+// LoadFromFile loads an OSCAL profile in JSON format
+func LoadFromFile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing profile %s: %w", path, err)
+	}
+	profile.Source = path
+	return profile, nil
+}
+
+// Parse parses an OSCAL profile in JSON format, with or without the top-level
+// "profile" wrapper
+func Parse(data []byte) (*Profile, error) {
+	var document oscalTypes.OscalCompleteSchema
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	profile := document.Profile
+	if profile == nil {
+		profile = &oscalTypes.Profile{}
+		if err := json.Unmarshal(data, profile); err != nil {
+			return nil, err
+		}
+		if profile.UUID == "" || len(profile.Imports) == 0 {
+			return nil, fmt.Errorf("document is not an OSCAL profile")
+		}
+	}
+
+	return New(profile), nil
+}
+
+// New indexes the control selections of an OSCAL profile
+func New(profile *oscalTypes.Profile) *Profile {
+	p := &Profile{
+		Profile:      profile,
+		included:     make(map[string]bool),
+		excluded:     make(map[string]bool),
+		withChildren: make(map[string]bool),
+	}
+
+	for _, imported := range profile.Imports {
+		if imported.IncludeAll != nil {
+			p.includeAll = true
+		}
+		if imported.IncludeControls != nil {
+			for _, selection := range *imported.IncludeControls {
+				p.addSelection(selection)
+			}
+		}
+		if imported.ExcludeControls != nil {
+			for _, selection := range *imported.ExcludeControls {
+				if selection.WithIds != nil {
+					for _, id := range *selection.WithIds {
+						p.excluded[strings.ToLower(id)] = true
+					}
+				}
+			}
+		}
+	}
+
+	return p
+}
+
+func (p *Profile) addSelection(selection oscalTypes.SelectControlById) {
+	if selection.WithIds != nil {
+		for _, id := range *selection.WithIds {
+			id = strings.ToLower(id)
+			if !p.included[id] {
+				p.included[id] = true
+				p.controls = append(p.controls, id)
+			}
+			if selection.WithChildControls == "yes" {
+				p.withChildren[id] = true
+			}
+		}
+	}
+	if selection.Matching != nil {
+		for _, matching := range *selection.Matching {
+			if matching.Pattern != "" {
+				p.patterns = append(p.patterns, strings.ToLower(matching.Pattern))
+			}
+		}
+	}
+}
+
+// Title returns the title of the profile
+func (p *Profile) Title() string {
+	return p.Profile.Metadata.Title
+}
+
+// HasControl reports whether the profile selects the control, by id, by pattern,
+// as a child control of a selected control, or by including all controls
+func (p *Profile) HasControl(id string) bool {
+	id = strings.ToLower(id)
+	if p.excluded[id] {
+		return false
+	}
+	if p.includeAll || p.included[id] {
+		return true
+	}
+	for parent := id; strings.Contains(parent, "."); {
+		parent = parent[:strings.LastIndex(parent, ".")]
+		if p.withChildren[parent] {
+			return true
+		}
+	}
+	for _, pattern := range p.patterns {
+		if matched, _ := path.Match(pattern, id); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Controls returns the ids of the controls the profile selects by id, in profile
+// order. Controls selected by pattern, as child controls or by including all
+// controls are not listed.
+func (p *Profile) Controls() []string {
+	controls := make([]string, 0, len(p.controls))
+	for _, id := range p.controls {
+		if !p.excluded[id] {
+			controls = append(controls, id)
+		}
+	}
+	return controls
+}
+
+// Enumerable reports whether Controls lists every control the profile selects
+func (p *Profile) Enumerable() bool {
+	return !p.includeAll && len(p.patterns) == 0 && len(p.withChildren) == 0
+}
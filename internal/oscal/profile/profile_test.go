@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testProfileJSON = `{
+  "profile": {
+    "uuid": "1c7b1e5e-3b8a-4c0e-9b9a-2f3c4d5e6f70",
+    "metadata": {
+      "title": "Test Baseline",
+      "last-modified": "2025-01-01T00:00:00Z",
+      "version": "1.0",
+      "oscal-version": "1.1.3"
+    },
+    "imports": [
+      {
+        "href": "#84cbf061-eb87-4ec1-8112-1f529232e907",
+        "include-controls": [
+          {
+            "with-ids": ["ac-1", "AC-2", "ac-2.1", "cm-6"]
+          },
+          {
+            "with-child-controls": "yes",
+            "with-ids": ["au-2"]
+          }
+        ],
+        "exclude-controls": [
+          {
+            "with-ids": ["ac-1"]
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func TestParse(t *testing.T) {
+	profile, err := Parse([]byte(testProfileJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if profile.Title() != "Test Baseline" {
+		t.Errorf("Title() = %q, expected Test Baseline", profile.Title())
+	}
+
+	expected := []string{"ac-2", "ac-2.1", "cm-6", "au-2"}
+	if controls := profile.Controls(); !reflect.DeepEqual(controls, expected) {
+		t.Errorf("Controls() = %v, expected %v", controls, expected)
+	}
+	if profile.Enumerable() {
+		t.Error("Enumerable() = true for a profile with child control selections, expected false")
+	}
+
+	if _, err := Parse([]byte(`{"catalog": {"uuid": "x"}}`)); err == nil {
+		t.Error("Parse() of a catalog returned no error")
+	}
+}
+
+func TestHasControl(t *testing.T) {
+	profile, err := Parse([]byte(testProfileJSON))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	testCases := []struct {
+		id       string
+		expected bool
+	}{
+		{"ac-2", true},
+		{"AC-2.1", true},
+		{"ac-2.13", false},
+		{"ac-1", false},
+		{"au-2.1", true},
+		{"si-4", false},
+	}
+
+	for _, tc := range testCases {
+		if result := profile.HasControl(tc.id); result != tc.expected {
+			t.Errorf("HasControl(%s) = %t, expected %t", tc.id, result, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package oval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// ApplySummary counts the checklist rules updated by ApplyResults
+type ApplySummary struct {
+	Evaluated    int
+	NotAFinding  int
+	Open         int
+	Undetermined int
+}
+
+// DefinitionID returns the OVAL definition a rule's check refers to, if any
+func DefinitionID(rule cklb.STIGRule) string {
+	if rule.CheckContentRef == nil || !strings.HasPrefix(rule.CheckContentRef.Name, "oval:") {
+		return ""
+	}
+	return rule.CheckContentRef.Name
+}
+
+// StatusForResult maps a definition result to a CKLB status. Compliance and
+// inventory definitions are satisfied when true; vulnerability and patch
+// definitions describe a finding when true.
+func StatusForResult(result DefinitionResult) (string, bool) {
+	pass := ResultTrue
+	if result.Class == "vulnerability" || result.Class == "patch" {
+		pass = ResultFalse
+	}
+
+	switch result.Result {
+	case pass:
+		return "not_a_finding", true
+	case ResultTrue, ResultFalse:
+		return "open", true
+	case ResultNotApplicable:
+		return "not_applicable", true
+	default:
+		return "", false
+	}
+}
+
+// ApplyResults sets the status and finding details of every checklist rule
+// whose check refers to an evaluated OVAL definition. Rules with an
+// undetermined result keep their status.
+func ApplyResults(checklist *cklb.Checklist, results map[string]DefinitionResult) ApplySummary {
+	var summary ApplySummary
+
+	for i := range checklist.Data.STIGs {
+		for j := range checklist.Data.STIGs[i].Rules {
+			rule := &checklist.Data.STIGs[i].Rules[j]
+
+			result, ok := results[DefinitionID(*rule)]
+			if !ok {
+				continue
+			}
+			summary.Evaluated++
+
+			details := fmt.Sprintf("OVAL definition %s evaluated to %s.", result.ID, result.Result)
+			if len(result.Messages) > 0 {
+				details += "\n" + strings.Join(result.Messages, "\n")
+			}
+			rule.FindingDetails = details
+
+			status, ok := StatusForResult(result)
+			if !ok {
+				summary.Undetermined++
+				continue
+			}
+			rule.Status = status
+
+			switch status {
+			case "not_a_finding":
+				summary.NotAFinding++
+			case "open":
+				summary.Open++
+			}
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,560 @@
+package oval
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Item represents a collected system item. Entities may have several values,
+// such as the subexpressions of a textfilecontent54 item.
+type Item map[string][]string
+
+// errUnsupported is returned for objects this evaluator cannot collect
+var errUnsupported = errors.New("unsupported")
+
+// skippedDirs are never walked when resolving path patterns
+var skippedDirs = map[string]bool{"/proc": true, "/sys": true, "/dev": true, "/run": true}
+
+// collector gathers items from the host or a mounted root filesystem
+type collector struct {
+	root string
+}
+
+// collect gathers the items described by an object
+func (c *collector) collect(object *Entity) ([]Item, error) {
+	for _, field := range object.Fields {
+		switch field.XMLName.Local {
+		case "set", "filter":
+			return nil, fmt.Errorf("%w: %s in object %s", errUnsupported, field.XMLName.Local, object.ID)
+		}
+		if field.VarRef != "" {
+			return nil, fmt.Errorf("%w: variable reference %s in object %s", errUnsupported, field.VarRef, object.ID)
+		}
+	}
+
+	switch object.XMLName.Local {
+	case "textfilecontent54_object":
+		return c.collectTextFileContent(object)
+	case "file_object":
+		return c.collectFiles(object)
+	case "family_object":
+		return []Item{{"family": {family()}}}, nil
+	case "dpkginfo_object":
+		return c.collectDpkgInfo(object)
+	case "rpminfo_object":
+		return c.collectRpmInfo(object)
+	case "sysctl_object":
+		return c.collectSysctl(object)
+	default:
+		return nil, fmt.Errorf("%w: object type %s", errUnsupported, object.XMLName.Local)
+	}
+}
+
+// field returns the named entity of an object or state
+func field(entity *Entity, name string) *Field {
+	for i := range entity.Fields {
+		if entity.Fields[i].XMLName.Local == name {
+			return &entity.Fields[i]
+		}
+	}
+	return nil
+}
+
+// matches reports whether a value satisfies an object entity
+func matches(f *Field, value string) (bool, error) {
+	return compareValue(f.Operation, f.Datatype, value, strings.TrimSpace(f.Value))
+}
+
+// maxSymlinks bounds the symbolic links followed while resolving a path under a
+// mounted root, as the kernel does
+const maxSymlinks = 40
+
+// hostPath maps a path on the evaluated system to a path on this host. Under a
+// mounted root, symbolic links are resolved one path component at a time against
+// the root, so an absolute link such as /etc/foo -> /etc/shadow reads the root's
+// file and not the host's, and paths that would leave the root are refused.
+func (c *collector) hostPath(systemPath string) (string, error) {
+	if filepath.Clean(c.root) == "/" {
+		return filepath.Join("/", filepath.FromSlash(systemPath)), nil
+	}
+
+	pending := strings.Split(filepath.ToSlash(systemPath), "/")
+	resolved := "/"
+	links := 0
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			if resolved == "/" {
+				return "", fmt.Errorf("path %s leaves the root %s", systemPath, c.root)
+			}
+			resolved = path.Dir(resolved)
+			continue
+		}
+
+		next := path.Join(resolved, name)
+		info, err := os.Lstat(filepath.Join(c.root, filepath.FromSlash(next)))
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("path %s has too many levels of symbolic links", systemPath)
+		}
+		target, err := os.Readlink(filepath.Join(c.root, filepath.FromSlash(next)))
+		if err != nil {
+			return "", err
+		}
+		if path.IsAbs(filepath.ToSlash(target)) {
+			resolved = "/"
+		}
+		pending = append(strings.Split(filepath.ToSlash(target), "/"), pending...)
+	}
+
+	return filepath.Join(c.root, filepath.FromSlash(resolved)), nil
+}
+
+// resolvePaths returns the existing paths described by the filepath or the
+// path and filename entities of an object
+func (c *collector) resolvePaths(object *Entity) ([]string, error) {
+	if fp := field(object, "filepath"); fp != nil {
+		return c.resolveEntity(fp, false)
+	}
+
+	pathField := field(object, "path")
+	if pathField == nil {
+		return nil, fmt.Errorf("object %s has no filepath or path", object.ID)
+	}
+	dirs, err := c.resolveEntity(pathField, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nameField := field(object, "filename")
+	if nameField == nil || strings.TrimSpace(nameField.Value) == "" {
+		// A nil filename refers to the directory itself
+		return dirs, nil
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		hostDir, err := c.hostPath(dir)
+		if err != nil {
+			continue
+		}
+		entries, err := os.ReadDir(hostDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			ok, err := matches(nameField, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				paths = append(paths, filepath.ToSlash(filepath.Join(dir, entry.Name())))
+			}
+		}
+	}
+	return paths, nil
+}
+
+// resolveEntity resolves a path entity using either equality or a pattern walk
+func (c *collector) resolveEntity(f *Field, dirs bool) ([]string, error) {
+	value := strings.TrimSpace(f.Value)
+
+	switch f.Operation {
+	case "", "equals":
+		hostPath, err := c.hostPath(value)
+		if err != nil {
+			return nil, nil
+		}
+		info, err := os.Stat(hostPath)
+		if err != nil || info.IsDir() != dirs {
+			return nil, nil
+		}
+		return []string{value}, nil
+	case "pattern match":
+		return c.walkPattern(value, dirs)
+	default:
+		return nil, fmt.Errorf("%w: path operation %q", errUnsupported, f.Operation)
+	}
+}
+
+// walkPattern walks from the literal prefix of a path pattern and returns every matching path
+func (c *collector) walkPattern(pattern string, dirs bool) ([]string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: pattern %q: %v", errUnsupported, pattern, err)
+	}
+
+	// Patterns without a literal directory to start from would walk the whole tree
+	start := literalPrefix(pattern)
+	i := strings.LastIndex(start, "/")
+	if !strings.HasPrefix(start, "/") || i <= 0 {
+		return nil, fmt.Errorf("%w: pattern %q does not start with a literal directory", errUnsupported, pattern)
+	}
+	start = start[:i]
+
+	hostStart, err := c.hostPath(start)
+	if err != nil {
+		return nil, nil
+	}
+	root, err := c.hostPath("/")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	err = filepath.WalkDir(hostStart, func(hostPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, hostPath)
+		if relErr != nil {
+			return nil
+		}
+		path := "/" + filepath.ToSlash(rel)
+		if rel == "." {
+			path = "/"
+		}
+		if entry.IsDir() && skippedDirs[path] {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() == dirs && re.MatchString(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	return paths, err
+}
+
+// literalPrefix returns the leading literal part of an anchored pattern
+func literalPrefix(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "^")
+
+	var prefix strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '\\' && i+1 < len(pattern) && strings.ContainsRune(`./-_`, rune(pattern[i+1])) {
+			prefix.WriteByte(pattern[i+1])
+			i++
+			continue
+		}
+		if strings.ContainsRune(`.*+?()[]{}|\^$`, rune(ch)) {
+			break
+		}
+		prefix.WriteByte(ch)
+	}
+	return prefix.String()
+}
+
+// collectTextFileContent collects one item per pattern match in each file
+func (c *collector) collectTextFileContent(object *Entity) ([]Item, error) {
+	patternField := field(object, "pattern")
+	if patternField == nil {
+		return nil, fmt.Errorf("object %s has no pattern", object.ID)
+	}
+
+	flags := "(?m)"
+	if behaviors := field(object, "behaviors"); behaviors != nil {
+		if behaviors.Multiline == "false" {
+			flags = ""
+		}
+		if behaviors.Singleline == "true" {
+			flags += "(?s)"
+		}
+	}
+	re, err := regexp.Compile(flags + patternField.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: pattern %q: %v", errUnsupported, patternField.Value, err)
+	}
+
+	paths, err := c.resolvePaths(object)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceField := field(object, "instance")
+
+	var items []Item
+	for _, path := range paths {
+		hostPath, err := c.hostPath(path)
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(hostPath)
+		if err != nil {
+			continue
+		}
+
+		for i, match := range re.FindAllStringSubmatch(string(content), -1) {
+			instance := strconv.Itoa(i + 1)
+			if instanceField != nil {
+				ok, err := compareValue(instanceField.Operation, "int", instance, strings.TrimSpace(instanceField.Value))
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+			}
+
+			item := Item{
+				"filepath": {path},
+				"path":     {filepath.ToSlash(filepath.Dir(path))},
+				"filename": {filepath.Base(path)},
+				"pattern":  {patternField.Value},
+				"instance": {instance},
+				"text":     {match[0]},
+			}
+			if len(match) > 1 {
+				item["subexpression"] = match[1:]
+			}
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// collectFiles collects the metadata of each matching file
+func (c *collector) collectFiles(object *Entity) ([]Item, error) {
+	paths, err := c.resolvePaths(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, path := range paths {
+		hostPath, err := c.hostPath(path)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(hostPath)
+		if err != nil {
+			continue
+		}
+
+		mode := info.Mode()
+		fileType := "regular"
+		if mode.IsDir() {
+			fileType = "directory"
+		}
+
+		item := Item{
+			"filepath": {path},
+			"path":     {filepath.ToSlash(filepath.Dir(path))},
+			"filename": {filepath.Base(path)},
+			"type":     {fileType},
+			"size":     {strconv.FormatInt(info.Size(), 10)},
+			"uread":    {strconv.FormatBool(mode&0400 != 0)},
+			"uwrite":   {strconv.FormatBool(mode&0200 != 0)},
+			"uexec":    {strconv.FormatBool(mode&0100 != 0)},
+			"gread":    {strconv.FormatBool(mode&0040 != 0)},
+			"gwrite":   {strconv.FormatBool(mode&0020 != 0)},
+			"gexec":    {strconv.FormatBool(mode&0010 != 0)},
+			"oread":    {strconv.FormatBool(mode&0004 != 0)},
+			"owrite":   {strconv.FormatBool(mode&0002 != 0)},
+			"oexec":    {strconv.FormatBool(mode&0001 != 0)},
+			"suid":     {strconv.FormatBool(mode&os.ModeSetuid != 0)},
+			"sgid":     {strconv.FormatBool(mode&os.ModeSetgid != 0)},
+			"sticky":   {strconv.FormatBool(mode&os.ModeSticky != 0)},
+		}
+		if uid, gid, ok := fileOwner(info); ok {
+			item["user_id"] = []string{uid}
+			item["group_id"] = []string{gid}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// family returns the OVAL family of the running platform
+func family() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "windows"
+	case "darwin":
+		return "macos"
+	default:
+		return "unix"
+	}
+}
+
+// packageItem builds a package item from its name, architecture and epoch:version-release
+func packageItem(name, arch, epoch, version, release string) Item {
+	if epoch == "" || epoch == "(none)" {
+		epoch = "0"
+	}
+	evr := epoch + ":" + version
+	if release != "" {
+		evr += "-" + release
+	}
+	return Item{
+		"name":    {name},
+		"arch":    {arch},
+		"epoch":   {epoch},
+		"version": {version},
+		"release": {release},
+		"evr":     {evr},
+	}
+}
+
+// filterPackages keeps the packages whose name satisfies the object's name entity
+func filterPackages(object *Entity, packages []Item) ([]Item, error) {
+	nameField := field(object, "name")
+	if nameField == nil {
+		return nil, fmt.Errorf("object %s has no name", object.ID)
+	}
+
+	var items []Item
+	for _, item := range packages {
+		ok, err := matches(nameField, item["name"][0])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// collectDpkgInfo reads installed packages from the dpkg status database
+func (c *collector) collectDpkgInfo(object *Entity) ([]Item, error) {
+	statusPath, err := c.hostPath("/var/lib/dpkg/status")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	file, err := os.Open(statusPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	var packages []Item
+	stanza := make(map[string]string)
+	flush := func() {
+		if stanza["Package"] != "" && strings.HasSuffix(stanza["Status"], " installed") {
+			epoch, version, release := splitDebianVersion(stanza["Version"])
+			packages = append(packages, packageItem(stanza["Package"], stanza["Architecture"], epoch, version, release))
+		}
+		stanza = make(map[string]string)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, " ") {
+			stanza[key] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return filterPackages(object, packages)
+}
+
+// splitDebianVersion splits [epoch:]upstream[-revision]
+func splitDebianVersion(version string) (string, string, string) {
+	epoch := "0"
+	if i := strings.Index(version, ":"); i >= 0 {
+		epoch = version[:i]
+		version = version[i+1:]
+	}
+	release := ""
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		release = version[i+1:]
+		version = version[:i]
+	}
+	return epoch, version, release
+}
+
+// collectRpmInfo queries installed packages through the rpm command
+func (c *collector) collectRpmInfo(object *Entity) ([]Item, error) {
+	rpm, err := exec.LookPath("rpm")
+	if err != nil {
+		return nil, fmt.Errorf("%w: rpminfo requires the rpm command", errUnsupported)
+	}
+
+	output, err := exec.Command(rpm, "--root", c.root, "-qa", "--qf", `%{NAME}\t%{ARCH}\t%{EPOCH}\t%{VERSION}\t%{RELEASE}\n`).Output()
+	if err != nil {
+		return nil, fmt.Errorf("rpm query failed: %w", err)
+	}
+
+	var packages []Item
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) == 5 {
+			packages = append(packages, packageItem(parts[0], parts[1], parts[2], parts[3], parts[4]))
+		}
+	}
+
+	return filterPackages(object, packages)
+}
+
+// collectSysctl reads a kernel parameter from /proc/sys. Kernel parameters are
+// only available on the running host, not under a mounted root filesystem,
+// whose empty /proc would otherwise read as parameters that do not exist.
+func (c *collector) collectSysctl(object *Entity) ([]Item, error) {
+	if filepath.Clean(c.root) != "/" {
+		return nil, fmt.Errorf("%w: sysctl object %s under mounted root %s", errUnsupported, object.ID, c.root)
+	}
+
+	nameField := field(object, "name")
+	if nameField == nil {
+		return nil, fmt.Errorf("object %s has no name", object.ID)
+	}
+	if nameField.Operation != "" && nameField.Operation != "equals" {
+		return nil, fmt.Errorf("%w: sysctl name operation %q", errUnsupported, nameField.Operation)
+	}
+
+	name := strings.TrimSpace(nameField.Value)
+	data, err := os.ReadFile(filepath.Join("/proc/sys", strings.ReplaceAll(name, ".", "/")))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return []Item{{"name": {name}, "value": {strings.TrimSpace(string(data))}}}, nil
+}
@@ -0,0 +1,301 @@
+package oval
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// compareValue compares an actual item value with the expected state value
+// using the operation and datatype of the state entity
+func compareValue(operation, datatype, actual, expected string) (bool, error) {
+	if operation == "" {
+		operation = "equals"
+	}
+
+	switch operation {
+	case "pattern match":
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, fmt.Errorf("unsupported pattern %q: %w", expected, err)
+		}
+		return re.MatchString(actual), nil
+	case "case insensitive equals":
+		return strings.EqualFold(actual, expected), nil
+	case "case insensitive not equal":
+		return !strings.EqualFold(actual, expected), nil
+	}
+
+	order, err := compareOrdered(datatype, actual, expected)
+	if err != nil {
+		return false, err
+	}
+
+	switch operation {
+	case "equals":
+		return order == 0, nil
+	case "not equal":
+		return order != 0, nil
+	case "greater than":
+		return order > 0, nil
+	case "greater than or equal":
+		return order >= 0, nil
+	case "less than":
+		return order < 0, nil
+	case "less than or equal":
+		return order <= 0, nil
+	default:
+		return false, fmt.Errorf("unsupported operation %q", operation)
+	}
+}
+
+// compareOrdered returns -1, 0 or 1 depending on how actual orders against expected
+func compareOrdered(datatype, actual, expected string) (int, error) {
+	switch datatype {
+	case "int":
+		a, err := strconv.ParseInt(strings.TrimSpace(actual), 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid int value %q", actual)
+		}
+		e, err := strconv.ParseInt(strings.TrimSpace(expected), 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid int value %q", expected)
+		}
+		switch {
+		case a < e:
+			return -1, nil
+		case a > e:
+			return 1, nil
+		}
+		return 0, nil
+	case "boolean":
+		a, err := parseBool(actual)
+		if err != nil {
+			return 0, err
+		}
+		e, err := parseBool(expected)
+		if err != nil {
+			return 0, err
+		}
+		if a == e {
+			return 0, nil
+		}
+		return 1, nil
+	case "evr_string":
+		return compareEVR(actual, expected, false), nil
+	case "debian_evr_string":
+		return compareEVR(actual, expected, true), nil
+	case "version":
+		return compareVersion(actual, expected), nil
+	default:
+		return strings.Compare(actual, expected), nil
+	}
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean value %q", value)
+}
+
+// splitEVR splits an epoch:version-release string into its parts
+func splitEVR(evr string) (string, string, string) {
+	epoch := "0"
+	if i := strings.Index(evr, ":"); i >= 0 {
+		epoch = evr[:i]
+		evr = evr[i+1:]
+	}
+	release := ""
+	if i := strings.LastIndex(evr, "-"); i >= 0 {
+		release = evr[i+1:]
+		evr = evr[:i]
+	}
+	return epoch, evr, release
+}
+
+// compareEVR compares two epoch:version-release strings, using dpkg's
+// ordering for Debian versions and rpm's otherwise
+func compareEVR(a, b string, debian bool) int {
+	compare := compareVersion
+	if debian {
+		compare = compareDebianVersion
+	}
+
+	aEpoch, aVersion, aRelease := splitEVR(a)
+	bEpoch, bVersion, bRelease := splitEVR(b)
+
+	if order := compare(aEpoch, bEpoch); order != 0 {
+		return order
+	}
+	if order := compare(aVersion, bVersion); order != 0 {
+		return order
+	}
+	return compare(aRelease, bRelease)
+}
+
+// compareVersion compares two version strings the way rpmvercmp does: runs of
+// digits compare numerically and are newer than runs of letters, other
+// characters only separate runs, a tilde sorts before anything, even the end
+// of the version, and a caret sorts after the end of the version but before
+// anything else
+func compareVersion(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		a = strings.TrimLeftFunc(a, isVersionSeparator)
+		b = strings.TrimLeftFunc(b, isVersionSeparator)
+
+		aTilde, bTilde := strings.HasPrefix(a, "~"), strings.HasPrefix(b, "~")
+		if aTilde || bTilde {
+			if !aTilde {
+				return 1
+			}
+			if !bTilde {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		aCaret, bCaret := strings.HasPrefix(a, "^"), strings.HasPrefix(b, "^")
+		if aCaret || bCaret {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !aCaret:
+				return 1
+			case !bCaret:
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(a[0])
+		segment := isAlpha
+		if numeric {
+			segment = isDigit
+		}
+		aSeg, bSeg := leadingRun(a, segment), leadingRun(b, segment)
+		a, b = a[len(aSeg):], b[len(bSeg):]
+
+		// Segments of different types: numeric segments are newer
+		if bSeg == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			aSeg = strings.TrimLeft(aSeg, "0")
+			bSeg = strings.TrimLeft(bSeg, "0")
+			if len(aSeg) != len(bSeg) {
+				return sign(len(aSeg) - len(bSeg))
+			}
+		}
+		if order := strings.Compare(aSeg, bSeg); order != 0 {
+			return order
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// compareDebianVersion compares two version strings the way dpkg does: runs
+// of non-digits compare character by character, with letters sorting before
+// other characters and a tilde before anything, even the end of the version,
+// and runs of digits compare numerically
+func compareDebianVersion(a, b string) int {
+	for len(a) > 0 || len(b) > 0 {
+		for (len(a) > 0 && !isDigit(a[0])) || (len(b) > 0 && !isDigit(b[0])) {
+			if order := debianOrder(a) - debianOrder(b); order != 0 {
+				return sign(order)
+			}
+			if len(a) > 0 {
+				a = a[1:]
+			}
+			if len(b) > 0 {
+				b = b[1:]
+			}
+		}
+
+		aNum := strings.TrimLeft(leadingRun(a, isDigit), "0")
+		bNum := strings.TrimLeft(leadingRun(b, isDigit), "0")
+		a = strings.TrimLeft(a, "0123456789")
+		b = strings.TrimLeft(b, "0123456789")
+		if len(aNum) != len(bNum) {
+			return sign(len(aNum) - len(bNum))
+		}
+		if order := strings.Compare(aNum, bNum); order != 0 {
+			return order
+		}
+	}
+	return 0
+}
+
+// debianOrder returns the weight dpkg gives the first character of a version
+// while comparing runs of non-digits
+func debianOrder(version string) int {
+	switch {
+	case version == "" || isDigit(version[0]):
+		return 0
+	case isAlpha(version[0]):
+		return int(version[0])
+	case version[0] == '~':
+		return -1
+	}
+	return int(version[0]) + 256
+}
+
+// leadingRun returns the longest prefix of s whose bytes all satisfy match
+func leadingRun(s string, match func(byte) bool) string {
+	i := 0
+	for i < len(s) && match(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+// isVersionSeparator reports whether rpm skips r between version segments
+func isVersionSeparator(r rune) bool {
+	return r != '~' && r != '^' && !(r < utf8.RuneSelf && (isDigit(byte(r)) || isAlpha(byte(r))))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,239 @@
+package oval
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadFromFile loads OVAL definitions from a file
+func LoadFromFile(filename string) (*Definitions, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	return ParseReader(file)
+}
+
+// ParseReader parses OVAL definitions from a reader
+func ParseReader(reader io.Reader) (*Definitions, error) {
+	var definitions Definitions
+	decoder := xml.NewDecoder(reader)
+
+	if err := decoder.Decode(&definitions); err != nil {
+		return nil, err
+	}
+
+	return &definitions, nil
+}
+
+// Evaluator evaluates OVAL definitions against the local host or a mounted
+// root filesystem. Only the textfilecontent54, file, family, rpminfo,
+// dpkginfo and sysctl tests are supported; anything else evaluates to error.
+type Evaluator struct {
+	collector   collector
+	definitions map[string]*Definition
+	tests       map[string]*Entity
+	objects     map[string]*Entity
+	states      map[string]*Entity
+	testResults map[string]Result
+	defResults  map[string]*DefinitionResult
+	items       map[string][]Item
+	itemErrors  map[string]error
+}
+
+// NewEvaluator creates an evaluator for the given definitions. Files are read
+// relative to root, which is "/" for the local host.
+func NewEvaluator(definitions *Definitions, root string) *Evaluator {
+	if root == "" {
+		root = "/"
+	}
+
+	e := &Evaluator{
+		collector:   collector{root: root},
+		definitions: make(map[string]*Definition),
+		tests:       make(map[string]*Entity),
+		objects:     make(map[string]*Entity),
+		states:      make(map[string]*Entity),
+		testResults: make(map[string]Result),
+		defResults:  make(map[string]*DefinitionResult),
+		items:       make(map[string][]Item),
+		itemErrors:  make(map[string]error),
+	}
+
+	for i := range definitions.Definitions {
+		e.definitions[definitions.Definitions[i].ID] = &definitions.Definitions[i]
+	}
+	for i := range definitions.Tests.Entities {
+		e.tests[definitions.Tests.Entities[i].ID] = &definitions.Tests.Entities[i]
+	}
+	for i := range definitions.Objects.Entities {
+		e.objects[definitions.Objects.Entities[i].ID] = &definitions.Objects.Entities[i]
+	}
+	for i := range definitions.States.Entities {
+		e.states[definitions.States.Entities[i].ID] = &definitions.States.Entities[i]
+	}
+
+	return e
+}
+
+// EvaluateAll evaluates every definition and returns the results by definition id
+func (e *Evaluator) EvaluateAll() map[string]DefinitionResult {
+	results := make(map[string]DefinitionResult)
+	for id := range e.definitions {
+		results[id] = e.EvaluateDefinition(id)
+	}
+	return results
+}
+
+// EvaluateDefinition evaluates a single definition
+func (e *Evaluator) EvaluateDefinition(id string) DefinitionResult {
+	if result, ok := e.defResults[id]; ok {
+		return *result
+	}
+
+	definition, ok := e.definitions[id]
+	if !ok {
+		return DefinitionResult{ID: id, Result: ResultError, Messages: []string{fmt.Sprintf("definition %s not found", id)}}
+	}
+
+	result := &DefinitionResult{
+		ID:     id,
+		Title:  strings.TrimSpace(definition.Metadata.Title),
+		Class:  definition.Class,
+		Result: ResultNotEvaluated,
+	}
+	// Guard against definitions that extend themselves
+	e.defResults[id] = result
+
+	if definition.Criteria == nil {
+		result.Result = ResultUnknown
+		result.Messages = append(result.Messages, "definition has no criteria")
+		return *result
+	}
+
+	result.Result = e.evaluateCriteria(definition.Criteria, &result.Messages)
+	return *result
+}
+
+func (e *Evaluator) evaluateCriteria(criteria *Criteria, messages *[]string) Result {
+	var results []Result
+
+	for _, criterion := range criteria.Criterions {
+		results = append(results, negate(e.evaluateTest(criterion.TestRef, messages), criterion.Negate))
+	}
+	for _, extend := range criteria.ExtendDefinitions {
+		extended := e.EvaluateDefinition(extend.DefinitionRef)
+		*messages = append(*messages, extended.Messages...)
+		results = append(results, negate(extended.Result, extend.Negate))
+	}
+	for i := range criteria.Criteria {
+		results = append(results, e.evaluateCriteria(&criteria.Criteria[i], messages))
+	}
+
+	return negate(combine(criteria.Operator, results), criteria.Negate)
+}
+
+func (e *Evaluator) evaluateTest(id string, messages *[]string) Result {
+	if result, ok := e.testResults[id]; ok {
+		return result
+	}
+
+	result, err := e.runTest(id)
+	if err != nil {
+		*messages = append(*messages, fmt.Sprintf("%s: %v", id, err))
+	}
+	e.testResults[id] = result
+	return result
+}
+
+func (e *Evaluator) runTest(id string) (Result, error) {
+	test, ok := e.tests[id]
+	if !ok {
+		return ResultError, fmt.Errorf("test not found")
+	}
+	if test.Object == nil {
+		return ResultError, fmt.Errorf("test has no object")
+	}
+
+	items, err := e.collect(test.Object.ObjectRef)
+	if err != nil {
+		return ResultError, err
+	}
+
+	existence := checkExistence(test.CheckExistence, len(items))
+	if existence != ResultTrue || test.CheckExistence == ExistNone || len(test.States) == 0 || len(items) == 0 {
+		return existence, nil
+	}
+
+	var itemResults []Result
+	for _, item := range items {
+		var stateResults []Result
+		for _, ref := range test.States {
+			state, ok := e.states[ref.StateRef]
+			if !ok {
+				return ResultError, fmt.Errorf("state %s not found", ref.StateRef)
+			}
+			result, err := evaluateState(state, item)
+			if err != nil {
+				return ResultError, err
+			}
+			stateResults = append(stateResults, result)
+		}
+		itemResults = append(itemResults, combine(test.StateOperator, stateResults))
+	}
+
+	return combineCheck(test.Check, itemResults), nil
+}
+
+func (e *Evaluator) collect(id string) ([]Item, error) {
+	if items, ok := e.items[id]; ok {
+		return items, e.itemErrors[id]
+	}
+
+	object, ok := e.objects[id]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", id)
+	}
+
+	items, err := e.collector.collect(object)
+	e.items[id] = items
+	e.itemErrors[id] = err
+	return items, err
+}
+
+// evaluateState compares every entity of a state with the matching item entity
+func evaluateState(state *Entity, item Item) (Result, error) {
+	var results []Result
+
+	for _, f := range state.Fields {
+		if f.VarRef != "" {
+			return ResultError, fmt.Errorf("%w: variable reference %s in state %s", errUnsupported, f.VarRef, state.ID)
+		}
+
+		values, ok := item[f.XMLName.Local]
+		if !ok {
+			return ResultError, fmt.Errorf("item has no %s entity for state %s", f.XMLName.Local, state.ID)
+		}
+
+		var valueResults []Result
+		for _, value := range values {
+			ok, err := compareValue(f.Operation, f.Datatype, value, strings.TrimSpace(f.Value))
+			if err != nil {
+				return ResultError, err
+			}
+			valueResults = append(valueResults, boolResult(ok))
+		}
+		results = append(results, combineCheck(f.EntityCheck, valueResults))
+	}
+
+	return combine(OperatorAND, results), nil
+}
@@ -0,0 +1,140 @@
+package oval
+
+// Operators used by criteria and state_operator
+const (
+	OperatorAND = "AND"
+	OperatorOR  = "OR"
+	OperatorONE = "ONE"
+	OperatorXOR = "XOR"
+)
+
+// Check values used by tests and entity_check
+const (
+	CheckAll         = "all"
+	CheckAtLeastOne  = "at least one"
+	CheckNoneSatisfy = "none satisfy"
+	CheckNoneExist   = "none exist"
+	CheckOnlyOne     = "only one"
+	ExistAll         = "all_exist"
+	ExistAny         = "any_exist"
+	ExistAtLeastOne  = "at_least_one_exists"
+	ExistNone        = "none_exist"
+	ExistOnlyOne     = "only_one_exists"
+)
+
+type resultCounts map[Result]int
+
+func countResults(results []Result) resultCounts {
+	counts := make(resultCounts)
+	for _, result := range results {
+		counts[result]++
+	}
+	return counts
+}
+
+// undetermined returns the strongest non true/false result, if any
+func (c resultCounts) undetermined() (Result, bool) {
+	for _, result := range []Result{ResultError, ResultUnknown, ResultNotEvaluated} {
+		if c[result] > 0 {
+			return result, true
+		}
+	}
+	return "", false
+}
+
+// combine applies an OVAL operator to a set of results following the
+// operator truth tables of the OVAL specification
+func combine(operator string, results []Result) Result {
+	counts := countResults(results)
+	if len(results) == 0 || counts[ResultNotApplicable] == len(results) {
+		return ResultNotApplicable
+	}
+
+	switch operator {
+	case OperatorOR:
+		if counts[ResultTrue] > 0 {
+			return ResultTrue
+		}
+		if result, ok := counts.undetermined(); ok {
+			return result
+		}
+		return ResultFalse
+	case OperatorONE:
+		if counts[ResultTrue] > 1 {
+			return ResultFalse
+		}
+		if result, ok := counts.undetermined(); ok {
+			return result
+		}
+		if counts[ResultTrue] == 1 {
+			return ResultTrue
+		}
+		return ResultFalse
+	case OperatorXOR:
+		if result, ok := counts.undetermined(); ok {
+			return result
+		}
+		if counts[ResultTrue]%2 == 1 {
+			return ResultTrue
+		}
+		return ResultFalse
+	default:
+		if counts[ResultFalse] > 0 {
+			return ResultFalse
+		}
+		if result, ok := counts.undetermined(); ok {
+			return result
+		}
+		return ResultTrue
+	}
+}
+
+// combineCheck applies a check enumeration value to a set of results
+func combineCheck(check string, results []Result) Result {
+	switch check {
+	case CheckAtLeastOne:
+		return combine(OperatorOR, results)
+	case CheckOnlyOne:
+		return combine(OperatorONE, results)
+	case CheckNoneSatisfy, CheckNoneExist:
+		return negate(combine(OperatorOR, results), true)
+	default:
+		return combine(OperatorAND, results)
+	}
+}
+
+// negate inverts true and false results when requested
+func negate(result Result, negated bool) Result {
+	if !negated {
+		return result
+	}
+	switch result {
+	case ResultTrue:
+		return ResultFalse
+	case ResultFalse:
+		return ResultTrue
+	default:
+		return result
+	}
+}
+
+// checkExistence evaluates a check_existence value against the number of collected items
+func checkExistence(existence string, items int) Result {
+	switch existence {
+	case ExistAny:
+		return ResultTrue
+	case ExistNone:
+		return boolResult(items == 0)
+	case ExistOnlyOne:
+		return boolResult(items == 1)
+	default:
+		return boolResult(items > 0)
+	}
+}
+
+func boolResult(value bool) Result {
+	if value {
+		return ResultTrue
+	}
+	return ResultFalse
+}
@@ -0,0 +1,316 @@
+package oval
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+const testDefinitions = `<?xml version="1.0" encoding="UTF-8"?>
+<oval_definitions xmlns="http://oval.mitre.org/XMLSchema/oval-definitions-5"
+    xmlns:ind="http://oval.mitre.org/XMLSchema/oval-definitions-5#independent"
+    xmlns:unix="http://oval.mitre.org/XMLSchema/oval-definitions-5#unix"
+    xmlns:linux="http://oval.mitre.org/XMLSchema/oval-definitions-5#linux">
+  <definitions>
+    <definition id="oval:test:def:1" class="compliance" version="1">
+      <metadata><title>SSH root login is disabled</title></metadata>
+      <criteria operator="AND">
+        <extend_definition definition_ref="oval:test:def:5" />
+        <criterion test_ref="oval:test:tst:1" />
+      </criteria>
+    </definition>
+    <definition id="oval:test:def:2" class="compliance" version="1">
+      <metadata><title>IP forwarding is disabled</title></metadata>
+      <criteria><criterion test_ref="oval:test:tst:2" /></criteria>
+    </definition>
+    <definition id="oval:test:def:3" class="compliance" version="1">
+      <metadata><title>telnetd is not installed</title></metadata>
+      <criteria><criterion test_ref="oval:test:tst:3" negate="true" /></criteria>
+    </definition>
+    <definition id="oval:test:def:4" class="compliance" version="1">
+      <metadata><title>openssh-server is recent</title></metadata>
+      <criteria><criterion test_ref="oval:test:tst:4" /></criteria>
+    </definition>
+    <definition id="oval:test:def:5" class="inventory" version="1">
+      <metadata><title>Unix family</title></metadata>
+      <criteria><criterion test_ref="oval:test:tst:5" /></criteria>
+    </definition>
+    <definition id="oval:test:def:6" class="compliance" version="1">
+      <metadata><title>shadow is not world readable</title></metadata>
+      <criteria><criterion test_ref="oval:test:tst:6" /></criteria>
+    </definition>
+  </definitions>
+  <tests>
+    <ind:textfilecontent54_test id="oval:test:tst:1" check="all" check_existence="at_least_one_exists" version="1">
+      <ind:object object_ref="oval:test:obj:1" />
+      <ind:state state_ref="oval:test:ste:1" />
+    </ind:textfilecontent54_test>
+    <unix:sysctl_test id="oval:test:tst:2" check="all" version="1">
+      <unix:object object_ref="oval:test:obj:2" />
+      <unix:state state_ref="oval:test:ste:2" />
+    </unix:sysctl_test>
+    <linux:dpkginfo_test id="oval:test:tst:3" check="all" check_existence="at_least_one_exists" version="1">
+      <linux:object object_ref="oval:test:obj:3" />
+    </linux:dpkginfo_test>
+    <linux:dpkginfo_test id="oval:test:tst:4" check="all" version="1">
+      <linux:object object_ref="oval:test:obj:4" />
+      <linux:state state_ref="oval:test:ste:4" />
+    </linux:dpkginfo_test>
+    <ind:family_test id="oval:test:tst:5" check="all" version="1">
+      <ind:object object_ref="oval:test:obj:5" />
+      <ind:state state_ref="oval:test:ste:5" />
+    </ind:family_test>
+    <unix:file_test id="oval:test:tst:6" check="all" version="1">
+      <unix:object object_ref="oval:test:obj:6" />
+      <unix:state state_ref="oval:test:ste:6" />
+    </unix:file_test>
+  </tests>
+  <objects>
+    <ind:textfilecontent54_object id="oval:test:obj:1" version="1">
+      <ind:filepath operation="pattern match">^/etc/ssh/sshd_config\.d/.*\.conf$</ind:filepath>
+      <ind:pattern operation="pattern match">^\s*PermitRootLogin\s+(\S+)\s*$</ind:pattern>
+      <ind:instance datatype="int" operation="greater than or equal">1</ind:instance>
+    </ind:textfilecontent54_object>
+    <unix:sysctl_object id="oval:test:obj:2" version="1">
+      <unix:name>net.ipv4.ip_forward</unix:name>
+    </unix:sysctl_object>
+    <linux:dpkginfo_object id="oval:test:obj:3" version="1">
+      <linux:name>telnetd</linux:name>
+    </linux:dpkginfo_object>
+    <linux:dpkginfo_object id="oval:test:obj:4" version="1">
+      <linux:name>openssh-server</linux:name>
+    </linux:dpkginfo_object>
+    <ind:family_object id="oval:test:obj:5" version="1" />
+    <unix:file_object id="oval:test:obj:6" version="1">
+      <unix:path>/etc</unix:path>
+      <unix:filename>shadow</unix:filename>
+    </unix:file_object>
+  </objects>
+  <states>
+    <ind:textfilecontent54_state id="oval:test:ste:1" version="1">
+      <ind:subexpression operation="case insensitive equals">no</ind:subexpression>
+    </ind:textfilecontent54_state>
+    <unix:sysctl_state id="oval:test:ste:2" version="1">
+      <unix:value datatype="int">0</unix:value>
+    </unix:sysctl_state>
+    <linux:dpkginfo_state id="oval:test:ste:4" version="1">
+      <linux:evr datatype="debian_evr_string" operation="greater than or equal">1:8.9p1-3</linux:evr>
+    </linux:dpkginfo_state>
+    <ind:family_state id="oval:test:ste:5" version="1">
+      <ind:family>unix</ind:family>
+    </ind:family_state>
+    <unix:file_state id="oval:test:ste:6" version="1">
+      <unix:oread datatype="boolean">false</unix:oread>
+    </unix:file_state>
+  </states>
+</oval_definitions>`
+
+const testDpkgStatus = `Package: openssh-server
+Status: install ok installed
+Architecture: amd64
+Version: 1:8.9p1-3ubuntu0.10
+
+Package: telnetd
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 0.17-44
+`
+
+func writeTestFile(t *testing.T, root, path, content string, mode os.FileMode) {
+	t.Helper()
+	hostPath := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(hostPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hostPath, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(hostPath, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompareOrdered(t *testing.T) {
+	testCases := []struct {
+		datatype string
+		a, b     string
+		expected int
+	}{
+		{"version", "1.10", "1.9", 1},
+		{"version", "1.0", "1.0.0", -1},
+		{"version", "1.0a", "1.0.1", -1},
+		{"version", "1.0~rc1", "1.0", -1},
+		{"version", "1.0~~", "1.0~", -1},
+		{"version", "1.0~rc1", "1.0~rc2", -1},
+		{"version", "1.0^git1", "1.0", 1},
+		{"version", "1.0^git1", "1.0.1", -1},
+		{"version", "1.0^git1", "1.0~rc1", 1},
+		{"evr_string", "1:1.0-1", "0:2.0-1", 1},
+		{"evr_string", "0:1.0-2.el9", "0:1.0-10.el9", -1},
+		{"evr_string", "0:1.0~rc1-1", "0:1.0-1", -1},
+		{"evr_string", "1.0-1", "0:1.0-1", 0},
+		{"debian_evr_string", "1.0~rc1", "1.0", -1},
+		{"debian_evr_string", "1.0~~", "1.0~", -1},
+		{"debian_evr_string", "1.0~~a", "1.0~", -1},
+		{"debian_evr_string", "1.0a", "1.0", 1},
+		{"debian_evr_string", "1.0+b1", "1.0a", 1},
+		{"debian_evr_string", "1:1.0-1", "2.0-1", 1},
+		{"debian_evr_string", "1:8.9p1-3", "1:8.9p1-3ubuntu0.1", -1},
+		{"debian_evr_string", "2.36-9+deb12u4", "2.36-9+deb12u10", -1},
+		{"debian_evr_string", "1.0-1~bpo12+1", "1.0-1", -1},
+		{"debian_evr_string", "1.0-0", "1.0", 0},
+	}
+
+	for _, tc := range testCases {
+		order, err := compareOrdered(tc.datatype, tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("compareOrdered(%s, %s, %s) returned error: %v", tc.datatype, tc.a, tc.b, err)
+		}
+		if order != tc.expected {
+			t.Errorf("compareOrdered(%s, %s, %s) = %d, expected %d", tc.datatype, tc.a, tc.b, order, tc.expected)
+		}
+		if reverse, _ := compareOrdered(tc.datatype, tc.b, tc.a); reverse != -tc.expected {
+			t.Errorf("compareOrdered(%s, %s, %s) = %d, expected %d", tc.datatype, tc.b, tc.a, reverse, -tc.expected)
+		}
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "etc/ssh/sshd_config.d/50-hardening.conf", "# managed\nPermitRootLogin no\n", 0644)
+	writeTestFile(t, root, "var/lib/dpkg/status", testDpkgStatus, 0644)
+	writeTestFile(t, root, "etc/shadow", "root:*:19000::::::\n", 0640)
+
+	definitions, err := ParseReader(strings.NewReader(testDefinitions))
+	if err != nil {
+		t.Fatalf("ParseReader() returned error: %v", err)
+	}
+
+	results := NewEvaluator(definitions, root).EvaluateAll()
+
+	expected := map[string]Result{
+		"oval:test:def:1": ResultTrue,
+		"oval:test:def:2": ResultError,
+		"oval:test:def:3": ResultTrue,
+		"oval:test:def:4": ResultTrue,
+		"oval:test:def:5": ResultTrue,
+		"oval:test:def:6": ResultTrue,
+	}
+	for id, want := range expected {
+		if got := results[id]; got.Result != want {
+			t.Errorf("definition %s = %s, expected %s (messages: %v)", id, got.Result, want, got.Messages)
+		}
+	}
+}
+
+func TestSysctlMountedRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "proc/sys/net/ipv4/ip_forward", "0\n", 0644)
+
+	object := &Entity{ID: "oval:test:obj:2", Fields: []Field{{XMLName: xml.Name{Local: "name"}, Value: "net.ipv4.ip_forward"}}}
+	mounted := collector{root: root}
+	if _, err := mounted.collectSysctl(object); !errors.Is(err, errUnsupported) {
+		t.Errorf("collectSysctl() under a mounted root returned %v, expected an unsupported error", err)
+	}
+
+	definitions, err := ParseReader(strings.NewReader(testDefinitions))
+	if err != nil {
+		t.Fatalf("ParseReader() returned error: %v", err)
+	}
+	result := NewEvaluator(definitions, root).EvaluateAll()["oval:test:def:2"]
+	if _, ok := StatusForResult(result); ok {
+		t.Errorf("sysctl definition under a mounted root = %s, expected no checklist status", result.Result)
+	}
+
+	if _, err := os.Stat("/proc/sys/kernel/ostype"); err != nil {
+		t.Skip("no /proc/sys on this host")
+	}
+	host := collector{root: "/"}
+	object.Fields[0].Value = "kernel.ostype"
+	if items, err := host.collectSysctl(object); err != nil || len(items) != 1 {
+		t.Errorf("collectSysctl() on the host = %v, %v, expected kernel.ostype", items, err)
+	}
+}
+
+func TestMountedRootSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeTestFile(t, root, "etc/ssh/sshd_config", "PermitRootLogin no\n", 0600)
+	writeTestFile(t, outside, "shadow", "root:secret\n", 0600)
+	symlinks := map[string]string{
+		"etc/absolute":   "/etc/ssh/sshd_config",
+		"etc/relative":   "ssh/sshd_config",
+		"etc/host":       filepath.Join(outside, "shadow"),
+		"etc/escape":     "../../../../../../.." + filepath.Join(outside, "shadow"),
+		"etc/ssh/linked": "/etc",
+	}
+	for link, target := range symlinks {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := collector{root: root}
+	expected := filepath.Join(root, "etc", "ssh", "sshd_config")
+	for _, path := range []string{"/etc/absolute", "/etc/relative", "/etc/ssh/linked/ssh/sshd_config"} {
+		if hostPath, err := c.hostPath(path); err != nil || hostPath != expected {
+			t.Errorf("hostPath(%s) = %s, %v, expected %s", path, hostPath, err, expected)
+		}
+	}
+	for _, path := range []string{"/etc/host", "/etc/escape", "/../etc/ssh/sshd_config"} {
+		if hostPath, err := c.hostPath(path); err == nil {
+			t.Errorf("hostPath(%s) = %s, expected an error for a path outside the root", path, hostPath)
+		}
+	}
+
+	object := &Entity{ID: "oval:test:obj:9", Fields: []Field{
+		{XMLName: xml.Name{Local: "filepath"}, Value: "/etc/host"},
+		{XMLName: xml.Name{Local: "pattern"}, Value: "secret"},
+	}}
+	if items, err := c.collectTextFileContent(object); err != nil || len(items) != 0 {
+		t.Errorf("collectTextFileContent() through a link out of the root = %v, %v, expected no items", items, err)
+	}
+
+	for _, pattern := range []string{`.*shadow$`, `^/[a-z]+/shadow$`, `shadow`} {
+		if _, err := c.walkPattern(pattern, false); !errors.Is(err, errUnsupported) {
+			t.Errorf("walkPattern(%s) returned %v, expected an unsupported error", pattern, err)
+		}
+	}
+}
+
+func TestApplyResults(t *testing.T) {
+	checklist := &cklb.Checklist{Data: cklb.ChecklistFile{STIGs: []cklb.STIG{{
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-1r1", Status: "not_reviewed", CheckContentRef: &cklb.CheckContentRef{Name: "oval:test:def:1"}},
+			{RuleID: "SV-2r1", Status: "not_reviewed", CheckContentRef: &cklb.CheckContentRef{Name: "oval:test:def:2"}},
+			{RuleID: "SV-3r1", Status: "not_reviewed", CheckContentRef: &cklb.CheckContentRef{Name: "oval:test:def:3"}},
+			{RuleID: "SV-4r1", Status: "not_reviewed", CheckContentRef: &cklb.CheckContentRef{Name: "M"}},
+		},
+	}}}}
+
+	results := map[string]DefinitionResult{
+		"oval:test:def:1": {ID: "oval:test:def:1", Class: "compliance", Result: ResultTrue},
+		"oval:test:def:2": {ID: "oval:test:def:2", Class: "compliance", Result: ResultFalse},
+		"oval:test:def:3": {ID: "oval:test:def:3", Class: "compliance", Result: ResultError, Messages: []string{"unsupported"}},
+	}
+
+	summary := ApplyResults(checklist, results)
+	if summary.Evaluated != 3 || summary.NotAFinding != 1 || summary.Open != 1 || summary.Undetermined != 1 {
+		t.Errorf("ApplyResults() summary = %+v", summary)
+	}
+
+	rules := checklist.Data.STIGs[0].Rules
+	for i, want := range []string{"not_a_finding", "open", "not_reviewed", "not_reviewed"} {
+		if rules[i].Status != want {
+			t.Errorf("rule %s status = %s, expected %s", rules[i].RuleID, rules[i].Status, want)
+		}
+	}
+	if !strings.Contains(rules[2].FindingDetails, "unsupported") {
+		t.Errorf("rule SV-3r1 finding details = %q", rules[2].FindingDetails)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package oval
+
+import (
+	"os"
+)
+
+// fileOwner is not available on this platform
+func fileOwner(info os.FileInfo) (string, string, bool) {
+	return "", "", false
+}
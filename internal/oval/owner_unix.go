@@ -0,0 +1,18 @@
+//go:build unix
+
+package oval
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// fileOwner returns the numeric owner and group of a file
+func fileOwner(info os.FileInfo) (string, string, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", "", false
+	}
+	return strconv.FormatUint(uint64(stat.Uid), 10), strconv.FormatUint(uint64(stat.Gid), 10), true
+}
@@ -0,0 +1,126 @@
+package oval
+
+import (
+	"encoding/xml"
+)
+
+// Namespace is the OVAL definitions namespace, also used as the XCCDF check system
+const Namespace = "http://oval.mitre.org/XMLSchema/oval-definitions-5"
+
+// Definitions represents the root of an OVAL definitions document
+type Definitions struct {
+	XMLName     xml.Name     `xml:"oval_definitions"`
+	Generator   Generator    `xml:"generator"`
+	Definitions []Definition `xml:"definitions>definition"`
+	Tests       EntityList   `xml:"tests"`
+	Objects     EntityList   `xml:"objects"`
+	States      EntityList   `xml:"states"`
+}
+
+// Generator describes the tool and schema version that produced the document
+type Generator struct {
+	ProductName   string `xml:"product_name"`
+	SchemaVersion string `xml:"schema_version"`
+	Timestamp     string `xml:"timestamp"`
+}
+
+// Definition represents a single OVAL definition
+type Definition struct {
+	ID       string    `xml:"id,attr"`
+	Class    string    `xml:"class,attr"`
+	Version  string    `xml:"version,attr"`
+	Metadata Metadata  `xml:"metadata"`
+	Criteria *Criteria `xml:"criteria"`
+}
+
+// Metadata holds the descriptive part of a definition
+type Metadata struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+}
+
+// Criteria represents a logical combination of criteria, criterions and extended definitions
+type Criteria struct {
+	Operator          string             `xml:"operator,attr"`
+	Negate            bool               `xml:"negate,attr"`
+	Criteria          []Criteria         `xml:"criteria"`
+	Criterions        []Criterion        `xml:"criterion"`
+	ExtendDefinitions []ExtendDefinition `xml:"extend_definition"`
+}
+
+// Criterion references a single test
+type Criterion struct {
+	TestRef string `xml:"test_ref,attr"`
+	Negate  bool   `xml:"negate,attr"`
+	Comment string `xml:"comment,attr"`
+}
+
+// ExtendDefinition references another definition
+type ExtendDefinition struct {
+	DefinitionRef string `xml:"definition_ref,attr"`
+	Negate        bool   `xml:"negate,attr"`
+	Comment       string `xml:"comment,attr"`
+}
+
+// EntityList holds tests, objects or states of any platform schema
+type EntityList struct {
+	Entities []Entity `xml:",any"`
+}
+
+// Entity represents a test, object or state. The element name identifies
+// its type, for example textfilecontent54_test or dpkginfo_object.
+type Entity struct {
+	XMLName        xml.Name
+	ID             string     `xml:"id,attr"`
+	Comment        string     `xml:"comment,attr"`
+	Check          string     `xml:"check,attr"`
+	CheckExistence string     `xml:"check_existence,attr"`
+	StateOperator  string     `xml:"state_operator,attr"`
+	Object         *ObjectRef `xml:"object"`
+	States         []StateRef `xml:"state"`
+	Fields         []Field    `xml:",any"`
+}
+
+// ObjectRef references the object of a test
+type ObjectRef struct {
+	ObjectRef string `xml:"object_ref,attr"`
+}
+
+// StateRef references a state of a test
+type StateRef struct {
+	StateRef string `xml:"state_ref,attr"`
+}
+
+// Field represents an entity of an object or state, such as filepath or evr
+type Field struct {
+	XMLName     xml.Name
+	Operation   string `xml:"operation,attr"`
+	Datatype    string `xml:"datatype,attr"`
+	EntityCheck string `xml:"entity_check,attr"`
+	VarRef      string `xml:"var_ref,attr"`
+	Multiline   string `xml:"multiline,attr"`
+	Singleline  string `xml:"singleline,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Result is the outcome of evaluating a definition, criteria or test
+type Result string
+
+// Results as defined by the OVAL results schema
+const (
+	ResultTrue          Result = "true"
+	ResultFalse         Result = "false"
+	ResultError         Result = "error"
+	ResultUnknown       Result = "unknown"
+	ResultNotEvaluated  Result = "not evaluated"
+	ResultNotApplicable Result = "not applicable"
+)
+
+// DefinitionResult holds the result of evaluating a definition
+type DefinitionResult struct {
+	ID       string
+	Title    string
+	Class    string
+	Result   Result
+	Messages []string
+}
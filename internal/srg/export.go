@@ -0,0 +1,175 @@
+package srg
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/google/uuid"
+
+	"github.com/open-automation-construct/oscalctl/internal/oscal/common"
+)
+
+// Supported export formats
+const (
+	FormatCSV   = "csv"
+	FormatOSCAL = "oscal"
+)
+
+// WriteReport writes the trace to path in the given format
+func WriteReport(trace *Trace, format, source, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	switch format {
+	case FormatCSV:
+		return WriteCSV(trace, file)
+	case FormatOSCAL:
+		data, err := json.MarshalIndent(ToOSCAL(trace, source), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// WriteCSV writes one row per SRG requirement and STIG rule pair. Uncovered
+// requirements and unmatched STIG rules get a row of their own.
+func WriteCSV(trace *Trace, writer io.Writer) error {
+	w := csv.NewWriter(writer)
+
+	header := []string{
+		"coverage", "srg_id", "srg_base_id", "srg_rule_id", "srg_title",
+		"stig_id", "stig_group_id", "stig_rule_id", "stig_rule_version", "stig_rule_title",
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, mapping := range trace.Mappings {
+		req := mapping.Requirement
+		if len(mapping.Implementations) == 0 {
+			if err := w.Write([]string{"uncovered", req.ID, req.BaseID, req.RuleID, req.Title, "", "", "", "", ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, impl := range mapping.Implementations {
+			coverage := "covered"
+			if impl.BaseIDMatch {
+				coverage = "base-id-match"
+			}
+			row := []string{
+				coverage, req.ID, req.BaseID, req.RuleID, req.Title,
+				impl.STIGID, impl.GroupID, impl.RuleID, impl.RuleVersion, impl.Title,
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, impl := range trace.Unmatched {
+		row := []string{
+			"unmatched", strings.Join(impl.SRGIDs, " "), "", "", "",
+			impl.STIGID, impl.GroupID, impl.RuleID, impl.RuleVersion, impl.Title,
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// ToOSCAL converts the trace into a component definition for the STIG whose
+// implemented requirements are keyed by SRG requirement. Uncovered SRG
+// requirements are listed as props of the control implementation.
+func ToOSCAL(trace *Trace, source string) *oscalTypes.ComponentDefinition {
+	implementationSet := oscalTypes.ControlImplementationSet{
+		UUID:                    uuid.New().String(),
+		Source:                  source,
+		Description:             fmt.Sprintf("Traceability of %s to %s", trace.STIGTitle, trace.SRGTitle),
+		ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{},
+	}
+
+	var uncovered []oscalTypes.Property
+	for _, mapping := range trace.Mappings {
+		req := mapping.Requirement
+		if len(mapping.Implementations) == 0 {
+			uncovered = append(uncovered, oscalTypes.Property{
+				Name:  "uncovered-srg-requirement",
+				Ns:    common.Namespace,
+				Value: req.ID,
+			})
+			continue
+		}
+
+		var props []oscalTypes.Property
+		for _, impl := range mapping.Implementations {
+			prop := oscalTypes.Property{
+				Name:    "stig-rule",
+				Ns:      common.Namespace,
+				Value:   impl.RuleID,
+				Remarks: fmt.Sprintf("%s %s: %s", impl.STIGID, impl.RuleVersion, impl.Title),
+			}
+			if impl.BaseIDMatch {
+				prop.Class = "base-id-match"
+				prop.Remarks += fmt.Sprintf(" (matched by base SRG id %s only)", req.BaseID)
+			}
+			props = append(props, prop)
+		}
+
+		implementationSet.ImplementedRequirements = append(implementationSet.ImplementedRequirements,
+			oscalTypes.ImplementedRequirementControlImplementation{
+				UUID:        uuid.New().String(),
+				ControlId:   strings.ToLower(req.ID),
+				Description: req.Title,
+				Props:       &props,
+			})
+	}
+	if len(uncovered) > 0 {
+		implementationSet.Props = &uncovered
+	}
+
+	definedComponent := oscalTypes.DefinedComponent{
+		UUID:                   uuid.New().String(),
+		Type:                   "software",
+		Title:                  trace.STIGTitle,
+		Description:            fmt.Sprintf("SRG traceability for %s", trace.STIGTitle),
+		ControlImplementations: &[]oscalTypes.ControlImplementationSet{implementationSet},
+	}
+
+	return &oscalTypes.ComponentDefinition{
+		UUID: uuid.New().String(),
+		Metadata: oscalTypes.Metadata{
+			Title:        fmt.Sprintf("%s to %s traceability", trace.STIGTitle, trace.SRGTitle),
+			LastModified: time.Now(),
+			Version:      "1.0.0",
+			OscalVersion: "1.1.3",
+		},
+		Components: &[]oscalTypes.DefinedComponent{definedComponent},
+	}
+}
@@ -0,0 +1,180 @@
+package srg
+
+import (
+	"strings"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// Requirement represents a single requirement of a Security Requirements Guide
+type Requirement struct {
+	ID      string // SRG requirement, e.g. SRG-APP-000023-AAA-000030
+	BaseID  string // Core SRG id from the group title, e.g. SRG-APP-000023
+	GroupID string
+	RuleID  string
+	Title   string
+	CCIs    []string
+}
+
+// Implementation represents a STIG rule and the SRG requirements it cites
+type Implementation struct {
+	STIGID      string
+	GroupID     string
+	RuleID      string
+	RuleVersion string
+	Title       string
+	SRGIDs      []string
+	// BaseIDMatch is set when the rule cites none of the SRG's full requirement
+	// ids and was matched by a base id, which several requirements can share
+	BaseIDMatch bool
+}
+
+// Mapping links an SRG requirement to the STIG rules implementing it
+type Mapping struct {
+	Requirement     Requirement
+	Implementations []Implementation
+}
+
+// Trace is the result of tracing STIG rules back to their SRG requirements
+type Trace struct {
+	SRGTitle  string
+	STIGTitle string
+	// Mappings holds every SRG requirement in SRG order, covered or not
+	Mappings []Mapping
+	// Unmatched holds STIG rules whose SRG ids are not part of the SRG
+	Unmatched []Implementation
+}
+
+// RequirementsFromSTIG builds the SRG requirements from an SRG loaded as a STIG
+func RequirementsFromSTIG(stig cklb.STIG) []Requirement {
+	var requirements []Requirement
+
+	for _, rule := range stig.Rules {
+		baseID := rule.SRGID
+		if baseID == "" && len(rule.GroupTree) > 0 {
+			baseID = rule.GroupTree[0].Title
+		}
+		requirements = append(requirements, Requirement{
+			ID:      rule.RuleVersion,
+			BaseID:  baseID,
+			GroupID: rule.GroupID,
+			RuleID:  rule.RuleID,
+			Title:   rule.RuleTitle,
+			CCIs:    rule.CCIs,
+		})
+	}
+
+	return requirements
+}
+
+// RuleSRGIDs returns the SRG ids cited by a STIG rule. They are taken from
+// srg_id, the group tree titles and the rule version, in that order.
+func RuleSRGIDs(rule cklb.STIGRule) []string {
+	var ids []string
+	seen := make(map[string]bool)
+
+	add := func(id string) {
+		id = strings.TrimSpace(id)
+		if strings.HasPrefix(id, "SRG-") && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	add(rule.SRGID)
+	for _, group := range rule.GroupTree {
+		add(group.Title)
+	}
+	add(rule.RuleVersion)
+
+	return ids
+}
+
+// BuildTrace maps the rules of the given STIGs to the requirements of the given SRGs
+func BuildTrace(srgs []cklb.STIG, stigs []cklb.STIG) *Trace {
+	trace := &Trace{
+		SRGTitle:  joinTitles(srgs),
+		STIGTitle: joinTitles(stigs),
+	}
+
+	// Index requirements by their full SRG ids, and separately by their base ids
+	index := make(map[string][]int)
+	baseIndex := make(map[string][]int)
+	for _, s := range srgs {
+		for _, requirement := range RequirementsFromSTIG(s) {
+			position := len(trace.Mappings)
+			trace.Mappings = append(trace.Mappings, Mapping{Requirement: requirement})
+
+			index[requirement.ID] = append(index[requirement.ID], position)
+			if requirement.BaseID != "" && requirement.BaseID != requirement.ID {
+				baseIndex[requirement.BaseID] = append(baseIndex[requirement.BaseID], position)
+			}
+		}
+	}
+
+	for _, s := range stigs {
+		for _, rule := range s.Rules {
+			implementation := Implementation{
+				STIGID:      s.STIGID,
+				GroupID:     rule.GroupID,
+				RuleID:      rule.RuleID,
+				RuleVersion: rule.RuleVersion,
+				Title:       rule.RuleTitle,
+				SRGIDs:      RuleSRGIDs(rule),
+			}
+
+			// Base ids are only matched for rules that cite no full requirement id
+			positions := matchPositions(implementation.SRGIDs, index)
+			if len(positions) == 0 {
+				positions = matchPositions(implementation.SRGIDs, baseIndex)
+				implementation.BaseIDMatch = len(positions) > 0
+			}
+			matched := make(map[int]bool)
+			for _, position := range positions {
+				if !matched[position] {
+					matched[position] = true
+					trace.Mappings[position].Implementations = append(trace.Mappings[position].Implementations, implementation)
+				}
+			}
+
+			if len(matched) == 0 {
+				trace.Unmatched = append(trace.Unmatched, implementation)
+			}
+		}
+	}
+
+	return trace
+}
+
+// matchPositions returns the positions of the requirements indexed under any of the ids
+func matchPositions(ids []string, index map[string][]int) []int {
+	var positions []int
+	for _, id := range ids {
+		positions = append(positions, index[id]...)
+	}
+	return positions
+}
+
+// Uncovered returns the SRG requirements that no STIG rule implements
+func (t *Trace) Uncovered() []Requirement {
+	var requirements []Requirement
+	for _, mapping := range t.Mappings {
+		if len(mapping.Implementations) == 0 {
+			requirements = append(requirements, mapping.Requirement)
+		}
+	}
+	return requirements
+}
+
+// Coverage returns the number of covered SRG requirements and the total number of requirements
+func (t *Trace) Coverage() (int, int) {
+	return len(t.Mappings) - len(t.Uncovered()), len(t.Mappings)
+}
+
+func joinTitles(stigs []cklb.STIG) string {
+	var titles []string
+	for _, s := range stigs {
+		titles = append(titles, s.STIGName)
+	}
+	return strings.Join(titles, ", ")
+}
@@ -0,0 +1,65 @@
+package srg
+
+import (
+	"testing"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+func TestBuildTrace(t *testing.T) {
+	srgs := []cklb.STIG{{
+		STIGName: "Test SRG",
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-1r1", RuleVersion: "SRG-APP-000001-AAA-000010", SRGID: "SRG-APP-000001"},
+			{RuleID: "SV-2r1", RuleVersion: "SRG-APP-000002-AAA-000020", GroupTree: []cklb.GroupTree{{Title: "SRG-APP-000002"}}},
+			{RuleID: "SV-3r1", RuleVersion: "SRG-APP-000002-AAA-000030", GroupTree: []cklb.GroupTree{{Title: "SRG-APP-000002"}}},
+		},
+	}}
+	stigs := []cklb.STIG{{
+		STIGName: "Test STIG",
+		STIGID:   "Test_STIG",
+		Rules: []cklb.STIGRule{
+			{RuleID: "SV-10r1", RuleVersion: "TEST-00-000010", GroupTree: []cklb.GroupTree{{Title: "SRG-APP-000001"}}},
+			{RuleID: "SV-11r1", RuleVersion: "TEST-00-000020", GroupTree: []cklb.GroupTree{{Title: "SRG-OS-000001-GPOS-00001"}}},
+			{RuleID: "SV-12r1", RuleVersion: "TEST-00-000030", SRGID: "SRG-APP-000002-AAA-000030", GroupTree: []cklb.GroupTree{{Title: "SRG-APP-000002"}}},
+		},
+	}}
+
+	trace := BuildTrace(srgs, stigs)
+
+	covered, total := trace.Coverage()
+	if covered != 2 || total != 3 {
+		t.Errorf("Coverage() = %d of %d, expected 2 of 3", covered, total)
+	}
+
+	if got := trace.Mappings[0].Implementations; len(got) != 1 || got[0].RuleID != "SV-10r1" || !got[0].BaseIDMatch {
+		t.Errorf("Mappings[0].Implementations = %v, expected SV-10r1 matched by base id", got)
+	}
+
+	// A full id match does not also cover the other requirements sharing its base id
+	if got := trace.Mappings[2].Implementations; len(got) != 1 || got[0].RuleID != "SV-12r1" || got[0].BaseIDMatch {
+		t.Errorf("Mappings[2].Implementations = %v, expected SV-12r1 matched by full id", got)
+	}
+
+	uncovered := trace.Uncovered()
+	if len(uncovered) != 1 || uncovered[0].ID != "SRG-APP-000002-AAA-000020" {
+		t.Errorf("Uncovered() = %v, expected SRG-APP-000002-AAA-000020", uncovered)
+	}
+
+	if len(trace.Unmatched) != 1 || trace.Unmatched[0].RuleID != "SV-11r1" {
+		t.Errorf("Unmatched = %v, expected SV-11r1", trace.Unmatched)
+	}
+}
+
+func TestRuleSRGIDs(t *testing.T) {
+	rule := cklb.STIGRule{
+		SRGID:       "SRG-APP-000023",
+		RuleVersion: "SRG-APP-000023-AAA-000030",
+		GroupTree:   []cklb.GroupTree{{Title: "SRG-APP-000023"}},
+	}
+
+	ids := RuleSRGIDs(rule)
+	if len(ids) != 2 || ids[0] != "SRG-APP-000023" || ids[1] != "SRG-APP-000023-AAA-000030" {
+		t.Errorf("RuleSRGIDs() = %v", ids)
+	}
+}
@@ -0,0 +1,261 @@
+package xccdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DataStreamCollection represents a SCAP 1.2 / 1.3 source data stream collection
+type DataStreamCollection struct {
+	XMLName     xml.Name     `xml:"data-stream-collection"`
+	ID          string       `xml:"id,attr"`
+	DataStreams []DataStream `xml:"data-stream"`
+	Components  []Component  `xml:"component"`
+	// Attrs holds the other attributes, including namespace declarations
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+// DataStream represents a single data stream within a collection
+type DataStream struct {
+	ID           string         `xml:"id,attr"`
+	ScapVersion  string         `xml:"scap-version,attr"`
+	UseCase      string         `xml:"use-case,attr"`
+	Timestamp    string         `xml:"timestamp,attr"`
+	Dictionaries []ComponentRef `xml:"dictionaries>component-ref"`
+	Checklists   []ComponentRef `xml:"checklists>component-ref"`
+	Checks       []ComponentRef `xml:"checks>component-ref"`
+}
+
+// ComponentRef references a component from a data stream
+type ComponentRef struct {
+	ID      string       `xml:"id,attr"`
+	Href    string       `xml:"href,attr"`
+	Catalog []CatalogURI `xml:"catalog>uri"`
+}
+
+// CatalogURI maps a file name used in the benchmark to a component reference
+type CatalogURI struct {
+	Name string `xml:"name,attr"`
+	URI  string `xml:"uri,attr"`
+}
+
+// Component holds one embedded document such as an XCCDF benchmark or OVAL definitions
+type Component struct {
+	ID        string `xml:"id,attr"`
+	Timestamp string `xml:"timestamp,attr"`
+	Content   []byte `xml:",innerxml"`
+	// Attrs holds the other attributes, including namespace declarations
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+// DataStreamBenchmark is a benchmark found in a data stream
+type DataStreamBenchmark struct {
+	DataStreamID string
+	Ref          ComponentRef
+	Benchmark    *Benchmark
+}
+
+// ParseDataStreamCollection parses a source data stream collection from a reader
+func ParseDataStreamCollection(reader io.Reader) (*DataStreamCollection, error) {
+	var collection DataStreamCollection
+	decoder := xml.NewDecoder(reader)
+
+	if err := decoder.Decode(&collection); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// IsDataStreamCollection reports whether the document's root element is a data stream collection
+func IsDataStreamCollection(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "data-stream-collection"
+		}
+	}
+}
+
+// Component returns the component referenced by href, for example "#scap_..._comp_...-xccdf.xml"
+func (c *DataStreamCollection) Component(href string) *Component {
+	id := strings.TrimPrefix(href, "#")
+	for i := range c.Components {
+		if c.Components[i].ID == id {
+			return &c.Components[i]
+		}
+	}
+	return nil
+}
+
+// Benchmarks returns every XCCDF benchmark listed as a checklist in the collection
+func (c *DataStreamCollection) Benchmarks() ([]DataStreamBenchmark, error) {
+	var benchmarks []DataStreamBenchmark
+
+	for _, stream := range c.DataStreams {
+		for _, ref := range stream.Checklists {
+			component := c.Component(ref.Href)
+			if component == nil {
+				return nil, fmt.Errorf("component %s referenced by %s not found", ref.Href, ref.ID)
+			}
+
+			benchmark, err := ParseReader(bytes.NewReader(component.Content))
+			if err != nil {
+				return nil, fmt.Errorf("error parsing benchmark in component %s: %w", component.ID, err)
+			}
+
+			benchmarks = append(benchmarks, DataStreamBenchmark{
+				DataStreamID: stream.ID,
+				Ref:          ref,
+				Benchmark:    benchmark,
+			})
+		}
+	}
+
+	return benchmarks, nil
+}
+
+// Benchmark returns the benchmark with the given benchmark id or component reference id.
+// An empty id selects the first benchmark.
+func (c *DataStreamCollection) Benchmark(id string) (*DataStreamBenchmark, error) {
+	benchmarks, err := c.Benchmarks()
+	if err != nil {
+		return nil, err
+	}
+	if len(benchmarks) == 0 {
+		return nil, fmt.Errorf("data stream collection %s contains no benchmarks", c.ID)
+	}
+
+	for i := range benchmarks {
+		if id == "" || benchmarks[i].Benchmark.ID == id || benchmarks[i].Ref.ID == id {
+			return &benchmarks[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("benchmark %s not found in data stream collection", id)
+}
+
+// CheckContent returns the raw document a benchmark's check-content-ref href
+// points to, such as the benchmark's OVAL definitions
+func (c *DataStreamCollection) CheckContent(benchmark *DataStreamBenchmark, href string) ([]byte, error) {
+	for _, uri := range benchmark.Ref.Catalog {
+		if uri.Name != href {
+			continue
+		}
+
+		refID := strings.TrimPrefix(uri.URI, "#")
+		for _, stream := range c.DataStreams {
+			if stream.ID != benchmark.DataStreamID {
+				continue
+			}
+			for _, check := range stream.Checks {
+				if check.ID != refID {
+					continue
+				}
+				component := c.Component(check.Href)
+				if component == nil {
+					return nil, fmt.Errorf("component %s referenced by %s not found", check.Href, check.ID)
+				}
+				return c.Document(component)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("check content %s not found in data stream %s", href, benchmark.DataStreamID)
+}
+
+// CheckHrefs returns the distinct check-content-ref hrefs of the given check system used by the benchmark
+func (b *Benchmark) CheckHrefs(system string) []string {
+	var hrefs []string
+	seen := make(map[string]bool)
+
+	for _, grouped := range b.GetRules() {
+		for _, check := range grouped.Rule.Checks {
+			if check.System == system && check.ContentRef.Href != "" && !seen[check.ContentRef.Href] {
+				seen[check.ContentRef.Href] = true
+				hrefs = append(hrefs, check.ContentRef.Href)
+			}
+		}
+	}
+
+	return hrefs
+}
+
+// Document returns the content of a component as a standalone document. Namespace
+// prefixes declared on the collection or the component, such as dc, xlink or cpe,
+// are declared again on the document's root element unless it declares them itself.
+func (c *DataStreamCollection) Document(component *Component) ([]byte, error) {
+	var names []string
+	inScope := make(map[string]string)
+	for _, attrs := range [][]xml.Attr{c.Attrs, component.Attrs} {
+		for _, attr := range attrs {
+			name, ok := namespaceDeclaration(attr)
+			if !ok {
+				continue
+			}
+			if _, exists := inScope[name]; !exists {
+				names = append(names, name)
+			}
+			inScope[name] = attr.Value
+		}
+	}
+
+	// Find the root element and the namespaces it declares itself
+	decoder := xml.NewDecoder(bytes.NewReader(component.Content))
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nil, fmt.Errorf("component %s has no root element: %w", component.ID, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range start.Attr {
+			if name, ok := namespaceDeclaration(attr); ok {
+				delete(inScope, name)
+			}
+		}
+
+		nameEnd := offset + 1 + int64(len(start.Name.Local))
+		if start.Name.Space != "" {
+			nameEnd += int64(len(start.Name.Space)) + 1
+		}
+		var document bytes.Buffer
+		document.Write(component.Content[:nameEnd])
+		for _, name := range names {
+			uri, ok := inScope[name]
+			if !ok {
+				continue
+			}
+			document.WriteString(" " + name + `="`)
+			if err := xml.EscapeText(&document, []byte(uri)); err != nil {
+				return nil, err
+			}
+			document.WriteString(`"`)
+		}
+		document.Write(component.Content[nameEnd:])
+		return document.Bytes(), nil
+	}
+}
+
+// namespaceDeclaration returns the attribute name of a namespace declaration,
+// xmlns or xmlns:prefix, and whether the attribute is one
+func namespaceDeclaration(attr xml.Attr) (string, bool) {
+	switch {
+	case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+		return "xmlns", true
+	case attr.Name.Space == "xmlns" && attr.Name.Local != "xml":
+		return "xmlns:" + attr.Name.Local, true
+	}
+	return "", false
+}
@@ -0,0 +1,307 @@
+package xccdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// GroupedRule pairs a rule with the group that contains it
+type GroupedRule struct {
+	GroupID          string
+	GroupTitle       string
+	GroupDescription string
+	Rule             Rule
+}
+
+// LoadFromFile loads an XCCDF benchmark from a file. If the file is a SCAP
+// source data stream collection, its first benchmark is returned.
+func LoadFromFile(filename string) (*Benchmark, error) {
+	benchmarks, err := LoadBenchmarks(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(benchmarks) == 0 {
+		return nil, fmt.Errorf("no benchmark found in %s", filename)
+	}
+
+	return benchmarks[0], nil
+}
+
+// LoadBenchmarks loads every XCCDF benchmark from a standalone benchmark or a
+// SCAP source data stream collection
+func LoadBenchmarks(filename string) ([]*Benchmark, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsDataStreamCollection(data) {
+		benchmark, err := ParseReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return []*Benchmark{benchmark}, nil
+	}
+
+	collection, err := ParseDataStreamCollection(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	streamBenchmarks, err := collection.Benchmarks()
+	if err != nil {
+		return nil, err
+	}
+
+	var benchmarks []*Benchmark
+	for _, streamBenchmark := range streamBenchmarks {
+		benchmarks = append(benchmarks, streamBenchmark.Benchmark)
+	}
+	return benchmarks, nil
+}
+
+// LoadDataStreamCollection loads a SCAP source data stream collection from a file
+func LoadDataStreamCollection(filename string) (*DataStreamCollection, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing file: %v\n", err)
+		}
+	}()
+
+	return ParseDataStreamCollection(file)
+}
+
+// ParseReader parses an XCCDF benchmark from a reader
+func ParseReader(reader io.Reader) (*Benchmark, error) {
+	var benchmark Benchmark
+	decoder := xml.NewDecoder(reader)
+
+	if err := decoder.Decode(&benchmark); err != nil {
+		return nil, err
+	}
+
+	return &benchmark, nil
+}
+
+// ReleaseInfo returns the release-info plain-text of the benchmark
+func (b *Benchmark) ReleaseInfo() string {
+	for _, text := range b.PlainTexts {
+		if text.ID == "release-info" {
+			return strings.TrimSpace(text.Value)
+		}
+	}
+	return ""
+}
+
+// GetRules returns every rule in the benchmark in document order,
+// including rules nested in sub-groups
+func (b *Benchmark) GetRules() []GroupedRule {
+	var rules []GroupedRule
+
+	for _, rule := range b.Rules {
+		rules = append(rules, GroupedRule{Rule: rule})
+	}
+	for _, group := range b.Groups {
+		rules = appendGroupRules(rules, group)
+	}
+
+	return rules
+}
+
+func appendGroupRules(rules []GroupedRule, group Group) []GroupedRule {
+	for _, rule := range group.Rules {
+		rules = append(rules, GroupedRule{
+			GroupID:          group.ID,
+			GroupTitle:       strings.TrimSpace(group.Title),
+			GroupDescription: group.Description,
+			Rule:             rule,
+		})
+	}
+	for _, child := range group.Groups {
+		rules = appendGroupRules(rules, child)
+	}
+	return rules
+}
+
+// CCIs returns the CCI identifiers attached to the rule
+func (r Rule) CCIs() []string {
+	var ccis []string
+	for _, ident := range r.Idents {
+		if ident.System == CCIIdentSystem || ident.System == LegacyCCIIdentSystem {
+			ccis = append(ccis, strings.TrimSpace(ident.Value))
+		}
+	}
+	return ccis
+}
+
+// LegacyIDs returns the legacy vulnerability identifiers attached to the rule
+func (r Rule) LegacyIDs() []string {
+	var ids []string
+	for _, ident := range r.Idents {
+		if ident.System == LegacyIdentSystem {
+			ids = append(ids, strings.TrimSpace(ident.Value))
+		}
+	}
+	return ids
+}
+
+// CheckBySystem returns the first check of the rule that uses the given check
+// system, such as OVALCheckSystem. SCAP content may give a rule several checks,
+// for example an OVAL and an OCIL check.
+func (r Rule) CheckBySystem(system string) (Check, bool) {
+	for _, check := range r.Checks {
+		if check.System == system {
+			return check, true
+		}
+	}
+	return Check{}, false
+}
+
+// CheckContent returns the manual check procedure of the rule, from the first
+// check that has one
+func (r Rule) CheckContent() string {
+	for _, check := range r.Checks {
+		if check.Content != "" {
+			return check.Content
+		}
+	}
+	return ""
+}
+
+// CheckContentRef returns the external check content of the rule: that of its
+// OVAL check, or else of the first check that references external content
+func (r Rule) CheckContentRef() (CheckContentRef, bool) {
+	if check, ok := r.CheckBySystem(OVALCheckSystem); ok && check.ContentRef != (CheckContentRef{}) {
+		return check.ContentRef, true
+	}
+	for _, check := range r.Checks {
+		if check.ContentRef != (CheckContentRef{}) {
+			return check.ContentRef, true
+		}
+	}
+	return CheckContentRef{}, false
+}
+
+// ToSTIG converts the benchmark into the STIG representation used by CKLB checklists.
+// Every rule starts out as not_reviewed.
+func (b *Benchmark) ToSTIG() cklb.STIG {
+	stigUUID := uuid.New().String()
+	title := strings.TrimSpace(b.Title)
+
+	stig := cklb.STIG{
+		STIGName:            title,
+		DisplayName:         strings.TrimSuffix(title, " Security Technical Implementation Guide"),
+		STIGID:              shortID(b.ID, "benchmark"),
+		ReleaseInfo:         b.ReleaseInfo(),
+		Version:             strings.TrimSpace(b.Version),
+		UUID:                stigUUID,
+		ReferenceIdentifier: strings.TrimSpace(b.Reference.Identifier),
+	}
+
+	for _, grouped := range b.GetRules() {
+		rule := grouped.Rule
+		groupID := shortID(grouped.GroupID, "group")
+		ruleID := shortID(rule.ID, "rule")
+		stigRule := cklb.STIGRule{
+			UUID:                uuid.New().String(),
+			STIGUUID:            stigUUID,
+			GroupID:             groupID,
+			GroupIDSrc:          groupID,
+			RuleID:              strings.TrimSuffix(ruleID, "_rule"),
+			RuleIDSrc:           ruleID,
+			Weight:              rule.Weight,
+			Classification:      "Unclassified",
+			Severity:            rule.Severity,
+			RuleVersion:         strings.TrimSpace(rule.Version),
+			RuleTitle:           strings.TrimSpace(rule.Title),
+			GroupTitle:          strings.TrimSpace(rule.Title),
+			FixText:             rule.FixText.Value,
+			CheckContent:        rule.CheckContent(),
+			ReferenceIdentifier: strings.TrimSpace(rule.Reference.Identifier),
+			LegacyIDs:           rule.LegacyIDs(),
+			CCIs:                rule.CCIs(),
+			Status:              "not_reviewed",
+		}
+		stigRule.ApplyDescription(rule.Description)
+		if ref, ok := rule.CheckContentRef(); ok {
+			stigRule.CheckContentRef = &cklb.CheckContentRef{
+				Name: ref.Name,
+				Href: ref.Href,
+			}
+		}
+		if groupID != "" {
+			stigRule.GroupTree = []cklb.GroupTree{{
+				ID:          groupID,
+				Title:       grouped.GroupTitle,
+				Description: grouped.GroupDescription,
+			}}
+		}
+		if strings.HasPrefix(grouped.GroupTitle, "SRG-") {
+			stigRule.SRGID = grouped.GroupTitle
+		}
+
+		stig.Rules = append(stig.Rules, stigRule)
+	}
+	stig.Size = len(stig.Rules)
+
+	return stig
+}
+
+// shortID strips the xccdf_<namespace>_<kind>_ prefix XCCDF 1.2 puts on
+// group and rule ids, so "xccdf_mil.disa.stig_rule_SV-1000r1_rule" becomes "SV-1000r1_rule"
+func shortID(id, kind string) string {
+	if !strings.HasPrefix(id, "xccdf_") {
+		return id
+	}
+	marker := "_" + kind + "_"
+	if i := strings.Index(id, marker); i >= 0 {
+		return id[i+len(marker):]
+	}
+	return id
+}
+
+// ToChecklist creates a new CKLB checklist containing the benchmark
+func (b *Benchmark) ToChecklist() *cklb.Checklist {
+	return &cklb.Checklist{
+		Data: cklb.ChecklistFile{
+			Title:      strings.TrimSpace(b.Title),
+			ID:         uuid.New().String(),
+			TargetData: cklb.TargetData{TargetType: "Computing", Role: "None"},
+			STIGs:      []cklb.STIG{b.ToSTIG()},
+		},
+	}
+}
+
+// LoadSTIGs loads the STIGs from an XCCDF benchmark or data stream collection (.xml) or a CKLB checklist
+func LoadSTIGs(filename string) ([]cklb.STIG, error) {
+	if strings.EqualFold(filepath.Ext(filename), ".xml") {
+		benchmarks, err := LoadBenchmarks(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error loading XCCDF benchmark: %w", err)
+		}
+		var stigs []cklb.STIG
+		for _, benchmark := range benchmarks {
+			stigs = append(stigs, benchmark.ToSTIG())
+		}
+		return stigs, nil
+	}
+
+	checklist := &cklb.Checklist{}
+	if err := checklist.LoadFromFile(filename); err != nil {
+		return nil, fmt.Errorf("error loading checklist: %w", err)
+	}
+	checklist.NormalizeDescriptions()
+	return checklist.GetSTIGs(), nil
+}
@@ -0,0 +1,239 @@
+package xccdf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+// Namespace12 is the XCCDF 1.2 namespace used for exported results
+const Namespace12 = "http://checklists.nist.gov/xccdf/1.2"
+
+// FactNamespace prefixes the names of target facts that have no standard
+// XCCDF asset identification fact
+const FactNamespace = "https://github.com/open-automation-construct/oscalctl/ns/xccdf/fact/"
+
+// idPrefix is the reverse-DNS prefix DISA uses for XCCDF 1.2 ids
+const idPrefix = "xccdf_mil.disa.stig_"
+
+// ResultsDocument is an XCCDF 1.2 benchmark carrying a single TestResult
+type ResultsDocument struct {
+	XMLName    xml.Name    `xml:"Benchmark"`
+	Xmlns      string      `xml:"xmlns,attr"`
+	ID         string      `xml:"id,attr"`
+	Status     Status      `xml:"status"`
+	Title      string      `xml:"title"`
+	PlainTexts []PlainText `xml:"plain-text,omitempty"`
+	Version    string      `xml:"version"`
+	TestResult TestResult  `xml:"TestResult"`
+}
+
+// TestResult holds the results of evaluating a benchmark against a target
+type TestResult struct {
+	ID            string       `xml:"id,attr"`
+	StartTime     string       `xml:"start-time,attr,omitempty"`
+	EndTime       string       `xml:"end-time,attr"`
+	Title         string       `xml:"title,omitempty"`
+	Target        []string     `xml:"target"`
+	TargetAddress []string     `xml:"target-address,omitempty"`
+	TargetFacts   *TargetFacts `xml:"target-facts,omitempty"`
+	RuleResults   []RuleResult `xml:"rule-result"`
+	Score         Score        `xml:"score"`
+}
+
+// TargetFacts lists facts about the evaluated target
+type TargetFacts struct {
+	Facts []Fact `xml:"fact"`
+}
+
+// Fact represents a single target fact
+type Fact struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RuleResult holds the result of a single rule
+type RuleResult struct {
+	IDRef    string    `xml:"idref,attr"`
+	Severity string    `xml:"severity,attr,omitempty"`
+	Weight   string    `xml:"weight,attr,omitempty"`
+	Time     string    `xml:"time,attr,omitempty"`
+	Result   string    `xml:"result"`
+	Idents   []Ident   `xml:"ident"`
+	Messages []Message `xml:"message"`
+	Check    *Check    `xml:"check,omitempty"`
+}
+
+// Message carries free text such as finding details or comments
+type Message struct {
+	Severity string `xml:"severity,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// Score holds the benchmark score
+type Score struct {
+	System  string `xml:"system,attr"`
+	Maximum string `xml:"maximum,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// ResultForStatus maps a CKLB status to an XCCDF rule result
+func ResultForStatus(status string) string {
+	switch status {
+	case "not_a_finding":
+		return "pass"
+	case "open":
+		return "fail"
+	case "not_applicable":
+		return "notapplicable"
+	default:
+		return "notchecked"
+	}
+}
+
+// NewResultsDocuments builds one XCCDF results document per STIG in the checklist
+func NewResultsDocuments(checklist *cklb.Checklist, now time.Time) []*ResultsDocument {
+	timestamp := now.UTC().Format(time.RFC3339)
+	target := checklist.Data.TargetData
+
+	var documents []*ResultsDocument
+	for _, stig := range checklist.Data.STIGs {
+		testResult := TestResult{
+			ID:          idPrefix + "testresult_" + stig.STIGID,
+			StartTime:   timestamp,
+			EndTime:     timestamp,
+			Title:       fmt.Sprintf("%s results for %s", stig.DisplayName, checklist.Data.Title),
+			Target:      []string{targetName(target)},
+			TargetFacts: targetFacts(target),
+		}
+		if target.IPAddress != "" {
+			testResult.TargetAddress = []string{target.IPAddress}
+		}
+
+		var passed, scored float64
+		for _, rule := range stig.Rules {
+			result := ResultForStatus(rule.Status)
+			ruleResult := RuleResult{
+				IDRef:    idPrefix + "rule_" + ruleIDSrc(rule),
+				Severity: rule.Severity,
+				Weight:   rule.Weight,
+				Time:     timestamp,
+				Result:   result,
+			}
+			for _, cci := range rule.CCIs {
+				ruleResult.Idents = append(ruleResult.Idents, Ident{System: CCIIdentSystem, Value: cci})
+			}
+			if details := strings.TrimSpace(rule.FindingDetails); details != "" {
+				ruleResult.Messages = append(ruleResult.Messages, Message{Severity: "info", Value: details})
+			}
+			if comments := strings.TrimSpace(rule.Comments); comments != "" {
+				ruleResult.Messages = append(ruleResult.Messages, Message{Severity: "info", Value: "Comments: " + comments})
+			}
+			if rule.CheckContentRef != nil && strings.HasPrefix(rule.CheckContentRef.Name, "oval:") {
+				ruleResult.Check = &Check{
+					System:     OVALCheckSystem,
+					ContentRef: CheckContentRef{Href: rule.CheckContentRef.Href, Name: rule.CheckContentRef.Name},
+				}
+			}
+			testResult.RuleResults = append(testResult.RuleResults, ruleResult)
+
+			// Flat scoring over the rules that were evaluated to pass or fail
+			weight, err := strconv.ParseFloat(rule.Weight, 64)
+			if err != nil {
+				weight = 1
+			}
+			if result == "pass" || result == "fail" {
+				scored += weight
+				if result == "pass" {
+					passed += weight
+				}
+			}
+		}
+		testResult.Score = Score{
+			System:  "urn:xccdf:scoring:flat",
+			Maximum: strconv.FormatFloat(scored, 'f', -1, 64),
+			Value:   strconv.FormatFloat(passed, 'f', -1, 64),
+		}
+
+		document := &ResultsDocument{
+			Xmlns:      Namespace12,
+			ID:         idPrefix + "benchmark_" + stig.STIGID,
+			Status:     Status{Value: "accepted"},
+			Title:      stig.STIGName,
+			Version:    stig.Version,
+			TestResult: testResult,
+		}
+		if stig.ReleaseInfo != "" {
+			document.PlainTexts = []PlainText{{ID: "release-info", Value: stig.ReleaseInfo}}
+		}
+		documents = append(documents, document)
+	}
+
+	return documents
+}
+
+// Write writes the results document as indented XML
+func (d *ResultsDocument) Write(writer io.Writer) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func ruleIDSrc(rule cklb.STIGRule) string {
+	if rule.RuleIDSrc != "" {
+		return rule.RuleIDSrc
+	}
+	return rule.RuleID + "_rule"
+}
+
+func targetName(target cklb.TargetData) string {
+	switch {
+	case target.HostName != "":
+		return target.HostName
+	case target.FQDN != "":
+		return target.FQDN
+	case target.IPAddress != "":
+		return target.IPAddress
+	}
+	return "unknown"
+}
+
+// targetFacts maps the CKLB target data to XCCDF asset identification facts.
+// Target data without a standard fact is carried under FactNamespace.
+func targetFacts(target cklb.TargetData) *TargetFacts {
+	var facts []Fact
+	add := func(name, value string) {
+		if value != "" {
+			facts = append(facts, Fact{Name: name, Type: "string", Value: value})
+		}
+	}
+
+	add("urn:xccdf:fact:asset:identifier:host_name", target.HostName)
+	add("urn:xccdf:fact:asset:identifier:fqdn", target.FQDN)
+	add("urn:xccdf:fact:asset:identifier:ipv4", target.IPAddress)
+	add("urn:xccdf:fact:asset:identifier:mac", target.MACAddress)
+	add(FactNamespace+"target_type", target.TargetType)
+	add(FactNamespace+"role", target.Role)
+	add(FactNamespace+"technology_area", target.TechnologyArea)
+	add(FactNamespace+"web_db_site", target.WebDBSite)
+	add(FactNamespace+"web_db_instance", target.WebDBInstance)
+	add(FactNamespace+"comments", target.Comments)
+
+	if len(facts) == 0 {
+		return nil
+	}
+	return &TargetFacts{Facts: facts}
+}
@@ -0,0 +1,121 @@
+package xccdf
+
+import (
+	"encoding/xml"
+)
+
+// Ident systems used by DISA benchmarks
+const (
+	CCIIdentSystem       = "http://cyber.mil/cci"
+	LegacyCCIIdentSystem = "http://iase.disa.mil/cci"
+	LegacyIdentSystem    = "http://cyber.mil/legacy"
+)
+
+// OVALCheckSystem is the check system of rules with OVAL checks
+const OVALCheckSystem = "http://oval.mitre.org/XMLSchema/oval-definitions-5"
+
+// Benchmark represents the root of an XCCDF 1.1 / 1.2 benchmark document
+type Benchmark struct {
+	XMLName     xml.Name    `xml:"Benchmark"`
+	ID          string      `xml:"id,attr"`
+	Status      Status      `xml:"status"`
+	Title       string      `xml:"title"`
+	Description string      `xml:"description"`
+	Reference   Reference   `xml:"reference"`
+	PlainTexts  []PlainText `xml:"plain-text"`
+	Version     string      `xml:"version"`
+	Profiles    []Profile   `xml:"Profile"`
+	Groups      []Group     `xml:"Group"`
+	Rules       []Rule      `xml:"Rule"`
+}
+
+// Status represents the benchmark status and its date
+type Status struct {
+	Date  string `xml:"date,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// PlainText represents a reusable plain-text block such as release-info
+type PlainText struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Reference represents a Dublin Core reference attached to a benchmark or rule
+type Reference struct {
+	Href       string `xml:"href,attr"`
+	Title      string `xml:"title"`
+	Publisher  string `xml:"publisher"`
+	Source     string `xml:"source"`
+	Type       string `xml:"type"`
+	Subject    string `xml:"subject"`
+	Identifier string `xml:"identifier"`
+}
+
+// Profile represents a named selection of rules
+type Profile struct {
+	ID          string   `xml:"id,attr"`
+	Title       string   `xml:"title"`
+	Description string   `xml:"description"`
+	Selects     []Select `xml:"select"`
+}
+
+// Select represents a rule or group selection within a profile
+type Select struct {
+	IDRef    string `xml:"idref,attr"`
+	Selected bool   `xml:"selected,attr"`
+}
+
+// Group represents an XCCDF group, which in DISA content wraps a single rule
+type Group struct {
+	ID          string  `xml:"id,attr"`
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	Groups      []Group `xml:"Group"`
+	Rules       []Rule  `xml:"Rule"`
+}
+
+// Rule represents an individual XCCDF rule
+type Rule struct {
+	ID          string    `xml:"id,attr"`
+	Weight      string    `xml:"weight,attr"`
+	Severity    string    `xml:"severity,attr"`
+	Version     string    `xml:"version"`
+	Title       string    `xml:"title"`
+	Description string    `xml:"description"`
+	Reference   Reference `xml:"reference"`
+	Idents      []Ident   `xml:"ident"`
+	FixText     FixText   `xml:"fixtext"`
+	Fix         Fix       `xml:"fix"`
+	Checks      []Check   `xml:"check"`
+}
+
+// Ident represents an identifier such as a CCI or a legacy vulnerability id
+type Ident struct {
+	System string `xml:"system,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// FixText represents the fix text of a rule
+type FixText struct {
+	FixRef string `xml:"fixref,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// Fix represents the fix reference of a rule
+type Fix struct {
+	ID string `xml:"id,attr"`
+}
+
+// Check represents the check of a rule
+type Check struct {
+	System     string          `xml:"system,attr"`
+	ContentRef CheckContentRef `xml:"check-content-ref"`
+	Content    string          `xml:"check-content,omitempty"`
+}
+
+// CheckContentRef represents a reference to external check content
+type CheckContentRef struct {
+	Href string `xml:"href,attr"`
+	Name string `xml:"name,attr"`
+}
@@ -0,0 +1,253 @@
+package xccdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-automation-construct/oscalctl/internal/cklb"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	benchmark, err := LoadFromFile("../../references/xccdf/U_AAA_Services_SRG_V2R2_Manual-xccdf.xml")
+	if err != nil {
+		t.Fatalf("LoadFromFile() returned error: %v", err)
+	}
+
+	if benchmark.ID != "AAA_Services" {
+		t.Errorf("benchmark.ID = %s, expected AAA_Services", benchmark.ID)
+	}
+	if benchmark.ReleaseInfo() != "Release: 2 Benchmark Date: 30 Jan 2025" {
+		t.Errorf("benchmark.ReleaseInfo() = %s", benchmark.ReleaseInfo())
+	}
+
+	rules := benchmark.GetRules()
+	if len(rules) != 77 {
+		t.Fatalf("GetRules() returned %d rules, expected 77", len(rules))
+	}
+
+	first := rules[0]
+	if first.GroupID != "V-204636" || first.GroupTitle != "SRG-APP-000023" {
+		t.Errorf("first rule group = %s %s", first.GroupID, first.GroupTitle)
+	}
+	if ccis := first.Rule.CCIs(); len(ccis) != 1 || ccis[0] != "CCI-000015" {
+		t.Errorf("first rule CCIs = %v, expected [CCI-000015]", ccis)
+	}
+	if legacy := first.Rule.LegacyIDs(); len(legacy) != 2 {
+		t.Errorf("first rule legacy ids = %v, expected 2 ids", legacy)
+	}
+}
+
+func TestToSTIG(t *testing.T) {
+	benchmark, err := LoadFromFile("../../references/xccdf/U_AAA_Services_SRG_V2R2_Manual-xccdf.xml")
+	if err != nil {
+		t.Fatalf("LoadFromFile() returned error: %v", err)
+	}
+
+	stig := benchmark.ToSTIG()
+	if stig.Size != 77 || len(stig.Rules) != 77 {
+		t.Fatalf("ToSTIG() size = %d, rules = %d, expected 77", stig.Size, len(stig.Rules))
+	}
+
+	rule := stig.Rules[0]
+	if rule.RuleID != "SV-204636r1043176" {
+		t.Errorf("rule.RuleID = %s, expected SV-204636r1043176", rule.RuleID)
+	}
+	if rule.RuleVersion != "SRG-APP-000023-AAA-000030" {
+		t.Errorf("rule.RuleVersion = %s", rule.RuleVersion)
+	}
+	if rule.SRGID != "SRG-APP-000023" {
+		t.Errorf("rule.SRGID = %s, expected SRG-APP-000023", rule.SRGID)
+	}
+	if !strings.HasPrefix(rule.Discussion, "Enterprise environments make account management") {
+		t.Errorf("rule.Discussion = %s", rule.Discussion)
+	}
+	if strings.Contains(rule.Discussion, "<") {
+		t.Errorf("rule.Discussion still contains tags: %s", rule.Discussion)
+	}
+	if rule.Documentable != "false" {
+		t.Errorf("rule.Documentable = %s, expected false", rule.Documentable)
+	}
+	if rule.STIGUUID != stig.UUID {
+		t.Errorf("rule.STIGUUID = %s, expected %s", rule.STIGUUID, stig.UUID)
+	}
+}
+
+const testDataStream = `<?xml version="1.0" encoding="UTF-8"?>
+<ds:data-stream-collection xmlns:ds="http://scap.nist.gov/schema/scap/source/1.2"
+    xmlns:xlink="http://www.w3.org/1999/xlink"
+    xmlns:cat="urn:oasis:names:tc:entity:xmlns:xml:catalog"
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    id="scap_test_collection" schematron-version="1.3">
+  <ds:data-stream id="scap_test_datastream" scap-version="1.3" use-case="CONFIGURATION">
+    <ds:checklists>
+      <ds:component-ref id="scap_test_cref_xccdf.xml" xlink:href="#scap_test_comp_xccdf.xml">
+        <cat:catalog>
+          <cat:uri name="test-oval.xml" uri="#scap_test_cref_oval.xml" />
+        </cat:catalog>
+      </ds:component-ref>
+    </ds:checklists>
+    <ds:checks>
+      <ds:component-ref id="scap_test_cref_oval.xml" xlink:href="#scap_test_comp_oval.xml" />
+    </ds:checks>
+  </ds:data-stream>
+  <ds:component id="scap_test_comp_xccdf.xml" timestamp="2025-01-01T00:00:00">
+    <Benchmark xmlns="http://checklists.nist.gov/xccdf/1.2" id="xccdf_mil.disa.stig_benchmark_Test_STIG">
+      <title>Test Security Technical Implementation Guide</title>
+      <plain-text id="release-info">Release: 1 Benchmark Date: 01 Jan 2025</plain-text>
+      <version>1</version>
+      <Profile id="xccdf_mil.disa.stig_profile_MAC-1_Classified"><title>I - Mission Critical Classified</title></Profile>
+      <Group id="xccdf_mil.disa.stig_group_V-1000">
+        <title>SRG-OS-000480-GPOS-00227</title>
+        <Rule id="xccdf_mil.disa.stig_rule_SV-1000r1_rule" severity="high" weight="10.0">
+          <version>TEST-00-000010</version>
+          <title>The system must be tested.</title>
+          <description>&lt;VulnDiscussion&gt;Testing matters.&lt;/VulnDiscussion&gt;</description>
+          <reference href="https://cyber.mil"><dc:publisher>DISA</dc:publisher></reference>
+          <ident system="http://cyber.mil/cci">CCI-000366</ident>
+          <check system="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+            <check-content-ref href="test-oval.xml" name="oval:mil.disa.stig.test:def:1" />
+          </check>
+          <check system="http://scap.nist.gov/schema/ocil/2">
+            <check-content-ref href="test-ocil.xml" name="ocil:mil.disa.stig.test:questionnaire:1" />
+          </check>
+        </Rule>
+      </Group>
+    </Benchmark>
+  </ds:component>
+  <ds:component id="scap_test_comp_oval.xml" timestamp="2025-01-01T00:00:00">
+    <oval_definitions xmlns="http://oval.mitre.org/XMLSchema/oval-definitions-5">
+      <definitions><definition id="oval:mil.disa.stig.test:def:1" class="compliance" version="1" /></definitions>
+    </oval_definitions>
+  </ds:component>
+</ds:data-stream-collection>`
+
+func TestDataStreamCollection(t *testing.T) {
+	if !IsDataStreamCollection([]byte(testDataStream)) {
+		t.Fatal("IsDataStreamCollection() = false, expected true")
+	}
+
+	collection, err := ParseDataStreamCollection(strings.NewReader(testDataStream))
+	if err != nil {
+		t.Fatalf("ParseDataStreamCollection() returned error: %v", err)
+	}
+
+	benchmark, err := collection.Benchmark("")
+	if err != nil {
+		t.Fatalf("Benchmark() returned error: %v", err)
+	}
+	if benchmark.Benchmark.ID != "xccdf_mil.disa.stig_benchmark_Test_STIG" {
+		t.Errorf("benchmark id = %s", benchmark.Benchmark.ID)
+	}
+	if len(benchmark.Benchmark.Profiles) != 1 {
+		t.Errorf("benchmark has %d profiles, expected 1", len(benchmark.Benchmark.Profiles))
+	}
+
+	hrefs := benchmark.Benchmark.CheckHrefs("http://oval.mitre.org/XMLSchema/oval-definitions-5")
+	if len(hrefs) != 1 || hrefs[0] != "test-oval.xml" {
+		t.Fatalf("CheckHrefs() = %v, expected [test-oval.xml]", hrefs)
+	}
+
+	content, err := collection.CheckContent(benchmark, hrefs[0])
+	if err != nil {
+		t.Fatalf("CheckContent() returned error: %v", err)
+	}
+	if !strings.Contains(string(content), "oval:mil.disa.stig.test:def:1") {
+		t.Errorf("CheckContent() did not return the OVAL component")
+	}
+
+	stig := benchmark.Benchmark.ToSTIG()
+	if stig.Rules[0].RuleID != "SV-1000r1" || stig.Rules[0].GroupID != "V-1000" || stig.Rules[0].Discussion != "Testing matters." {
+		t.Errorf("ToSTIG() rule = %s %q", stig.Rules[0].RuleID, stig.Rules[0].Discussion)
+	}
+	if ref := stig.Rules[0].CheckContentRef; ref == nil || ref.Name != "oval:mil.disa.stig.test:def:1" {
+		t.Errorf("ToSTIG() check-content-ref = %+v, expected the OVAL definition ahead of the OCIL questionnaire", ref)
+	}
+
+	// The extracted benchmark declares the prefixes declared on the collection
+	document, err := collection.Document(collection.Component(benchmark.Ref.Href))
+	if err != nil {
+		t.Fatalf("Document() returned error: %v", err)
+	}
+	publisher := ""
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Document() is not well-formed: %v", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "publisher" {
+			publisher = start.Name.Space
+		}
+	}
+	if publisher != "http://purl.org/dc/elements/1.1/" {
+		t.Errorf("dc:publisher namespace = %q in extracted benchmark, expected the Dublin Core namespace:\n%s", publisher, document[:200])
+	}
+
+	if IsDataStreamCollection([]byte(`<Benchmark id="x"/>`)) {
+		t.Error("IsDataStreamCollection() = true for a standalone benchmark")
+	}
+}
+
+func TestNewResultsDocuments(t *testing.T) {
+	checklist := &cklb.Checklist{Data: cklb.ChecklistFile{
+		Title:      "Test",
+		TargetData: cklb.TargetData{HostName: "host01", IPAddress: "10.0.0.1", Role: "Member Server"},
+		STIGs: []cklb.STIG{{
+			STIGID: "Test_STIG",
+			Rules: []cklb.STIGRule{
+				{RuleID: "SV-1r1", RuleIDSrc: "SV-1r1_rule", Weight: "10.0", Status: "not_a_finding", FindingDetails: "Configured."},
+				{RuleID: "SV-2r1", Weight: "10.0", Status: "open", Comments: "Waiting on vendor."},
+				{RuleID: "SV-3r1", Status: "not_applicable"},
+				{RuleID: "SV-4r1", Status: "not_reviewed"},
+			},
+		}},
+	}}
+
+	documents := NewResultsDocuments(checklist, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(documents) != 1 {
+		t.Fatalf("NewResultsDocuments() returned %d documents, expected 1", len(documents))
+	}
+
+	result := documents[0].TestResult
+	if result.Target[0] != "host01" || result.TargetAddress[0] != "10.0.0.1" {
+		t.Errorf("target = %v %v", result.Target, result.TargetAddress)
+	}
+	facts := make(map[string]string)
+	for _, fact := range result.TargetFacts.Facts {
+		facts[fact.Name] = fact.Value
+	}
+	if facts["urn:xccdf:fact:asset:identifier:host_name"] != "host01" || facts[FactNamespace+"role"] != "Member Server" || len(facts) != 3 {
+		t.Errorf("target facts = %v", facts)
+	}
+
+	expected := []string{"pass", "fail", "notapplicable", "notchecked"}
+	for i, want := range expected {
+		if got := result.RuleResults[i].Result; got != want {
+			t.Errorf("rule-result[%d] = %s, expected %s", i, got, want)
+		}
+	}
+	if result.RuleResults[1].IDRef != "xccdf_mil.disa.stig_rule_SV-2r1_rule" {
+		t.Errorf("rule-result[1] idref = %s", result.RuleResults[1].IDRef)
+	}
+	if len(result.RuleResults[0].Messages) != 1 || result.RuleResults[0].Messages[0].Value != "Configured." {
+		t.Errorf("rule-result[0] messages = %v", result.RuleResults[0].Messages)
+	}
+	if result.Score.Value != "10" || result.Score.Maximum != "20" {
+		t.Errorf("score = %s of %s, expected 10 of 20", result.Score.Value, result.Score.Maximum)
+	}
+
+	var out strings.Builder
+	if err := documents[0].Write(&out); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `<TestResult id="xccdf_mil.disa.stig_testresult_Test_STIG"`) {
+		t.Errorf("Write() output missing TestResult:\n%s", out.String())
+	}
+}